@@ -2,7 +2,10 @@ package main
 
 import (
 	"os"
+	"public-alerts/internal/chains"
+	"public-alerts/internal/common"
 	"public-alerts/internal/config"
+	"public-alerts/internal/events"
 	"public-alerts/internal/monitor"
 	"public-alerts/internal/notify"
 	"time"
@@ -26,38 +29,158 @@ func main() {
 	log.Logger = log.With().Caller().Logger()
 	log.Info().Msg("Starting public-alerts")
 
+	// Create API clients shared by the monitors
+	thornodeClient, err := common.NewThornodeClient()
+	if err != nil {
+		log.Fatal().Err(err).Msg("failed to create thornode client")
+	}
+	nineRealmsClient := common.NewNineRealmsClient()
+	midgardClient := common.NewMidgardClient()
+	priceFetcher := common.NewPriceFetcher(thornodeClient, midgardClient)
+	adapters, err := chains.NewAdapters(config.Get().ChainDaemons)
+	if err != nil {
+		log.Fatal().Err(err).Msg("failed to create chain daemon adapters")
+	}
+
 	// Create Alert Channel
 	alertQueue := make(chan notify.Alert, 1)
 
 	// Chain Lag Monitor
-	chainLagMonitor := &monitor.ChainLagMonitor{}
+	chainLagMonitor := monitor.NewChainLagMonitor(thornodeClient)
 	// poll every 5 mins
 	monitor.Spawn(chainLagMonitor, alertQueue, 5*time.Minute)
 
-	// Solvency Monitor
-	solvencyMonitor := &monitor.SolvencyMonitor{}
-	monitor.Spawn(solvencyMonitor, alertQueue, 1*time.Minute)
+	// Solvency Monitor, against our own chain daemons when SOLVENCY_SOURCE=daemons
+	solvencyMonitor := monitor.NewSolvencyMonitor(nineRealmsClient, priceFetcher)
+	solvencyInterval := 1 * time.Minute
+	if config.Get().Solvency.Source == config.SolvencySourceDaemons {
+		solvencyMonitor = monitor.NewDaemonSolvencyMonitor(thornodeClient, adapters, priceFetcher)
+		// UTXO set scans take minutes on the larger chains
+		solvencyInterval = 15 * time.Minute
+	}
+	monitor.Spawn(solvencyMonitor, alertQueue, solvencyInterval)
 
 	// Invariant Monitor
-	invariantMonitor := monitor.NewInvariantsMonitor()
+	invariantMonitor := monitor.NewInvariantsMonitor(thornodeClient)
 	monitor.Spawn(invariantMonitor, alertQueue, 5*time.Minute)
 
 	// stuck outbound monitor
-	stuckOutboundMonitor := monitor.NewOutboundMonitor()
+	stuckOutboundMonitor := monitor.NewOutboundMonitor(thornodeClient)
 	monitor.Spawn(stuckOutboundMonitor, alertQueue, 10*time.Minute)
 
+	// Churn monitor
+	churnMonitor := monitor.NewChurnMonitor(thornodeClient)
+	monitor.Spawn(churnMonitor, alertQueue, 1*time.Minute)
+
+	// Node watchlist monitor, alerts each watched node's own receivers
+	nodeWatchlistMonitor := monitor.NewNodeWatchlistMonitor(thornodeClient, config.Get().WatchedNodes)
+	monitor.Spawn(nodeWatchlistMonitor, alertQueue, 1*time.Minute)
+
+	// Version monitor, following rollouts and upgrade proposals
+	versionMonitor := monitor.NewVersionMonitor(thornodeClient, config.Get().WatchedNodes)
+	monitor.Spawn(versionMonitor, alertQueue, 5*time.Minute)
+
+	// Mimir monitor
+	mimirMonitor := monitor.NewMimirMonitor(thornodeClient)
+	monitor.Spawn(mimirMonitor, alertQueue, 1*time.Minute)
+
+	// Chain halt monitor, cross-referencing solvency auto-halts with the solvency monitor
+	chainHaltMonitor := monitor.NewChainHaltMonitor(thornodeClient, solvencyMonitor)
+	monitor.Spawn(chainHaltMonitor, alertQueue, 1*time.Minute)
+
+	// Pool monitor
+	poolMonitor := monitor.NewPoolMonitor(thornodeClient, priceFetcher)
+	monitor.Spawn(poolMonitor, alertQueue, 1*time.Minute)
+
+	// Price deviation monitor, optionally against a reference price feed
+	var referencePrices common.PriceFetcher
+	if url := config.Get().Endpoints.PriceReference; url != "" {
+		referencePrices = common.NewReferencePriceFetcher(url)
+	}
+	priceDeviationMonitor := monitor.NewPriceDeviationMonitor(thornodeClient, referencePrices)
+	monitor.Spawn(priceDeviationMonitor, alertQueue, 1*time.Minute)
+
+	// Migration monitor, following the funds left in retiring vaults
+	migrationMonitor := monitor.NewMigrationMonitor(thornodeClient, priceFetcher)
+	monitor.Spawn(migrationMonitor, alertQueue, 5*time.Minute)
+
+	// Queue monitor, watching the outbound and scheduled queue backlog
+	queueMonitor := monitor.NewQueueMonitor(thornodeClient, priceFetcher)
+	monitor.Spawn(queueMonitor, alertQueue, 1*time.Minute)
+
+	// Economic security monitor, comparing the effective bond with pooled and vaulted value
+	economicSecurityMonitor := monitor.NewEconomicSecurityMonitor(thornodeClient)
+	monitor.Spawn(economicSecurityMonitor, alertQueue, 5*time.Minute)
+
+	// Savers monitor, synth utilisation, savers capacity and large savers withdrawals
+	saversMonitor := monitor.NewSaversMonitor(thornodeClient, priceFetcher)
+	monitor.Spawn(saversMonitor, alertQueue, 5*time.Minute)
+
+	// Supply monitor, unexpected RUNE mints and reserve drains go to the security webhooks
+	supplyMonitor := monitor.NewSupplyMonitor(thornodeClient)
+	monitor.Spawn(supplyMonitor, alertQueue, 1*time.Minute)
+
+	// Wallet monitor, alerting each watched wallet to its own webhooks
+	walletMonitor := monitor.NewWalletMonitor(thornodeClient, config.Get().WatchedWallets)
+	monitor.Spawn(walletMonitor, alertQueue, 5*time.Minute)
+
+	// Observation monitor, nodes whose bifrost stopped advancing a chain
+	observationMonitor := monitor.NewObservationMonitor(thornodeClient)
+	monitor.Spawn(observationMonitor, alertQueue, 1*time.Minute)
+
+	// Chain daemon monitor, health of the local daemons when any are configured
+	if len(adapters) > 0 {
+		chainDaemonMonitor := monitor.NewChainDaemonMonitor(thornodeClient, adapters)
+		monitor.Spawn(chainDaemonMonitor, alertQueue, 1*time.Minute)
+	}
+
+	// Inbound address monitor, router and unknown address changes go to the security webhooks
+	inboundMonitor := monitor.NewInboundMonitor(thornodeClient)
+	monitor.Spawn(inboundMonitor, alertQueue, 1*time.Minute)
+
+	// Whale monitor, reporting large swaps and liquidity actions from Midgard
+	whaleMonitor := monitor.NewWhaleMonitor(thornodeClient, midgardClient, priceFetcher)
+	monitor.Spawn(whaleMonitor, alertQueue, 1*time.Minute)
+
+	// Block production liveness monitor
+	livenessMonitor := monitor.NewLivenessMonitor(thornodeClient)
+	monitor.Spawn(livenessMonitor, alertQueue, 30*time.Second)
+
 	// Chain Update monitor
 	ChainUpdateMonitor := monitor.NewChainUpdateMonitor()
 	monitor.Spawn(ChainUpdateMonitor, alertQueue, 10*time.Minute)
 
 	// Image changes monitor
-	ImageChangesMonitor := monitor.NewImageChangeMonitor()
+	ImageChangesMonitor := monitor.NewImageChangeMonitor(nineRealmsClient)
 	monitor.Spawn(ImageChangesMonitor, alertQueue, 10*time.Minute)
 
 	// Security Update monitor
 	SecurityUpdatesMonitor := monitor.NewSecurityUpdatesMonitor()
 	monitor.Spawn(SecurityUpdatesMonitor, alertQueue, 10*time.Minute)
 
+	// Provider consistency monitor, only useful with more than one provider
+	providerClients, err := common.NewThornodeProviderClients(config.Get().ThornodeProviders)
+	if err != nil {
+		log.Fatal().Err(err).Msg("failed to create thornode provider clients")
+	}
+	if len(providerClients) > 1 {
+		providerConsistencyMonitor := monitor.NewProviderConsistencyMonitor(providerClients)
+		monitor.Spawn(providerConsistencyMonitor, alertQueue, 5*time.Minute)
+	}
+
+	// Event driven monitors, streamed from the preferred provider's RPC websocket
+	var eventSource events.Source = events.NewSubscriber(config.Get().ThornodeProviders[0].RPC, 0)
+	if recordFile := config.Get().Events.RecordFile; recordFile != "" {
+		f, err := os.OpenFile(recordFile, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+		if err != nil {
+			log.Fatal().Err(err).Msg("failed to open event record file")
+		}
+		defer f.Close()
+		eventSource = events.Recording(eventSource, f)
+	}
+	chainEventMonitor := monitor.NewChainEventMonitor()
+	monitor.SpawnEventMonitors(eventSource, alertQueue, chainEventMonitor)
+
 	// Spawn more monitors as needed...
 
 	for alert := range alertQueue {
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"flag"
+	"net/http"
+	"os"
+	"public-alerts/internal/common"
+	"public-alerts/internal/config"
+	"public-alerts/internal/httpreplay"
+	"public-alerts/internal/monitor"
+
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+)
+
+////////////////////////////////////////////////////////////////////////////////
+// MAIN
+////////////////////////////////////////////////////////////////////////////////
+
+// record runs the polled monitors the scenario tests replay, and the Nine Realms and
+// GitHub monitors, once against the configured endpoints and saves the THORNode,
+// Midgard, Nine Realms and GitHub responses as a replayable scenario.
+func main() {
+	out := flag.String("o", "scenario.json", "scenario file to write")
+	description := flag.String("d", "", "description of the captured scenario")
+	flag.Parse()
+
+	log.Logger = log.Output(zerolog.ConsoleWriter{Out: os.Stderr})
+
+	cfg := config.Get()
+	provider := cfg.ThornodeProviders[0]
+	recorder, err := httpreplay.NewRecorder(map[string]string{
+		httpreplay.ServiceThornode:    provider.API,
+		httpreplay.ServiceThornodeRPC: provider.RPC,
+		httpreplay.ServiceMidgard:     cfg.Endpoints.MidgardAPI,
+		httpreplay.ServiceNineRealms:  cfg.Endpoints.NineRealmsAPI,
+		httpreplay.ServiceGithub:      cfg.Endpoints.GithubAPI,
+	}, http.DefaultTransport)
+	if err != nil {
+		log.Fatal().Err(err).Msg("failed to create recorder")
+	}
+	// every client, including the Tendermint RPC client, uses the default transport
+	http.DefaultTransport = recorder
+
+	thornodeClient, err := common.NewThornodeClientWithProviders([]config.ThornodeProvider{provider})
+	if err != nil {
+		log.Fatal().Err(err).Msg("failed to create thornode client")
+	}
+	nineRealmsClient := common.NewNineRealmsClient()
+	priceFetcher := common.NewPriceFetcher(thornodeClient, common.NewMidgardClient())
+
+	monitors := []monitor.Monitor{
+		monitor.NewChainLagMonitor(thornodeClient),
+		monitor.NewSolvencyMonitor(nineRealmsClient, priceFetcher),
+		monitor.NewInvariantsMonitor(thornodeClient),
+		monitor.NewOutboundMonitor(thornodeClient),
+		monitor.NewChurnMonitor(thornodeClient),
+		monitor.NewMigrationMonitor(thornodeClient, priceFetcher),
+		monitor.NewImageChangeMonitor(nineRealmsClient),
+		monitor.NewSecurityUpdatesMonitor(),
+	}
+	for _, m := range monitors {
+		alerts, err := m.Check()
+		if err != nil {
+			log.Error().Err(err).Str("monitor", m.Name()).Msg("check failed")
+		}
+		for _, alert := range alerts {
+			log.Info().Str("monitor", m.Name()).Msg(alert.Text())
+		}
+	}
+
+	scenario := recorder.Scenario(*description)
+	if err := scenario.Save(*out); err != nil {
+		log.Fatal().Err(err).Msg("failed to save scenario")
+	}
+	log.Info().Int("interactions", len(scenario.Interactions)).Msgf("saved scenario to %s", *out)
+}
@@ -0,0 +1,168 @@
+package chains
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"public-alerts/internal/config"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// defaultHTTPTimeout bounds every request made to a chain daemon.
+	defaultHTTPTimeout = 10 * time.Second
+	// scanHTTPTimeout bounds UTXO set scans, which walk the whole chainstate.
+	scanHTTPTimeout = 10 * time.Minute
+)
+
+// ChainStatus is the sync state reported by a chain daemon.
+type ChainStatus struct {
+	Height  int64 // latest block the daemon has processed
+	Syncing bool  // the daemon, or its consensus client, is still catching up
+	Peers   int   // peers the daemon is connected to
+}
+
+// ChainAdapter queries the health of the daemon of one chain, and the balances held
+// on it, through the RPC of its client family.
+type ChainAdapter interface {
+	Chain() string
+	Status() (*ChainStatus, error)
+	// Balance returns the amount of a THORChain asset an address holds, in 1e8 units.
+	// EVM tokens are held by the THORChain router on behalf of the address, the
+	// router is ignored on other chains.
+	Balance(address, router, asset string) (int64, error)
+}
+
+// NewAdapter returns the adapter for the daemon's chain family.
+func NewAdapter(daemon config.ChainDaemon) (ChainAdapter, error) {
+	client := &http.Client{Timeout: defaultHTTPTimeout}
+	if daemon.Beacon != "" && daemon.Chain != "ETH" {
+		return nil, fmt.Errorf("chain %s does not run a beacon client", daemon.Chain)
+	}
+	switch daemon.Chain {
+	case "BTC", "BCH", "LTC", "DOGE":
+		scanClient := &http.Client{Timeout: scanHTTPTimeout}
+		return &bitcoinAdapter{chain: daemon.Chain, url: daemon.URL, client: client, scanClient: scanClient}, nil
+	case "ETH", "BSC":
+		return &evmAdapter{chain: daemon.Chain, url: daemon.URL, beacon: daemon.Beacon, client: client}, nil
+	case "GAIA":
+		return &gaiaAdapter{url: daemon.URL, client: client}, nil
+	case "AVAX":
+		return &avalancheAdapter{url: daemon.URL, client: client}, nil
+	}
+	return nil, fmt.Errorf("unsupported chain daemon %s", daemon.Chain)
+}
+
+// ReadsBalances reports whether the daemon of chain can read address balances.
+func ReadsBalances(chain string) bool {
+	return !noScanChains[chain]
+}
+
+// NewAdapters returns the adapters of all daemons.
+func NewAdapters(daemons []config.ChainDaemon) ([]ChainAdapter, error) {
+	adapters := make([]ChainAdapter, 0, len(daemons))
+	for _, daemon := range daemons {
+		adapter, err := NewAdapter(daemon)
+		if err != nil {
+			return nil, err
+		}
+		adapters = append(adapters, adapter)
+	}
+	return adapters, nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// HTTP
+////////////////////////////////////////////////////////////////////////////////
+
+type rpcRequest struct {
+	JSONRPC string      `json:"jsonrpc"`
+	ID      int         `json:"id"`
+	Method  string      `json:"method"`
+	Params  interface{} `json:"params"`
+}
+
+type rpcResponse struct {
+	Result json.RawMessage `json:"result"`
+	Error  *struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// redact removes the credentials of a daemon url, so it can be logged.
+func redact(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "<invalid url>"
+	}
+	return u.Redacted()
+}
+
+// callRPC posts a JSON-RPC call to endpoint and decodes its result into result.
+// Credentials in the endpoint are sent as basic auth. Daemons answer failed calls
+// with a non-200 status and an error body, so the body is decoded before the status
+// is checked.
+func callRPC(client *http.Client, endpoint, method string, params, result interface{}) error {
+	if params == nil {
+		params = []interface{}{}
+	}
+	body, err := json.Marshal(rpcRequest{JSONRPC: "2.0", ID: 1, Method: method, Params: params})
+	if err != nil {
+		return fmt.Errorf("error encoding %s: %w", method, err)
+	}
+	resp, err := client.Post(endpoint, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("error calling %s on %s: %w", method, redact(endpoint), stripURL(err))
+	}
+	defer resp.Body.Close()
+
+	var response rpcResponse
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		if resp.StatusCode != http.StatusOK {
+			return fmt.Errorf("unexpected status code %d calling %s on %s", resp.StatusCode, method, redact(endpoint))
+		}
+		return fmt.Errorf("error decoding %s: %w", method, err)
+	}
+	if response.Error != nil {
+		return fmt.Errorf("error calling %s on %s: %s (%d)", method, redact(endpoint), response.Error.Message, response.Error.Code)
+	}
+	if err := json.Unmarshal(response.Result, result); err != nil {
+		return fmt.Errorf("error decoding %s: %w", method, err)
+	}
+	return nil
+}
+
+// getJSON performs a GET request against endpoint and decodes the JSON response into target.
+func getJSON(client *http.Client, endpoint string, target interface{}) error {
+	resp, err := client.Get(endpoint)
+	if err != nil {
+		return fmt.Errorf("error requesting %s: %w", redact(endpoint), stripURL(err))
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status code %d from %s", resp.StatusCode, redact(endpoint))
+	}
+	if err := json.NewDecoder(resp.Body).Decode(target); err != nil {
+		return fmt.Errorf("error decoding %s: %w", redact(endpoint), err)
+	}
+	return nil
+}
+
+// stripURL returns the cause of a request error without the url, which may hold
+// credentials.
+func stripURL(err error) error {
+	if urlErr, ok := err.(*url.Error); ok {
+		return urlErr.Err
+	}
+	return err
+}
+
+// parseHex parses a 0x prefixed quantity as returned by EVM JSON-RPC.
+func parseHex(quantity string) (int64, error) {
+	return strconv.ParseInt(strings.TrimPrefix(quantity, "0x"), 16, 64)
+}
@@ -0,0 +1,98 @@
+package chains
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"public-alerts/internal/config"
+	"strings"
+	"testing"
+)
+
+// daemonStub is a chain daemon serving JSON-RPC results by method and GET responses
+// by path. A result may be a func answering the call's params. Unknown methods fail
+// like bitcoind, with a 500 status and an error body.
+type daemonStub struct {
+	*httptest.Server
+	results map[string]interface{}
+	paths   map[string]string
+	auth    []string // basic auth users of the requests
+}
+
+func newDaemonStub(t *testing.T, results map[string]interface{}, paths map[string]string) *daemonStub {
+	stub := &daemonStub{results: results, paths: paths}
+	stub.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, _, _ := r.BasicAuth()
+		stub.auth = append(stub.auth, user)
+		if r.Method == http.MethodGet {
+			body, ok := stub.paths[r.URL.Path]
+			if !ok {
+				http.NotFound(w, r)
+				return
+			}
+			_, _ = w.Write([]byte(body))
+			return
+		}
+
+		var req rpcRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("invalid JSON-RPC request: %v", err)
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		result, ok := stub.results[req.Method]
+		if !ok {
+			w.WriteHeader(http.StatusInternalServerError)
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{
+				"result": nil, "id": req.ID, "error": map[string]interface{}{"code": -32601, "message": "Method not found"},
+			})
+			return
+		}
+		if answer, ok := result.(func(params interface{}) interface{}); ok {
+			result = answer(req.Params)
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"jsonrpc": "2.0", "id": req.ID, "result": result})
+	}))
+	t.Cleanup(stub.Close)
+	return stub
+}
+
+func TestNewAdapter(t *testing.T) {
+	tests := []struct {
+		daemon  config.ChainDaemon
+		wantErr bool
+	}{
+		{config.ChainDaemon{Chain: "DOGE", URL: "http://dogecoin-daemon:22555"}, false},
+		{config.ChainDaemon{Chain: "ETH", URL: "http://ethereum-daemon:8545", Beacon: "http://ethereum-daemon:3500"}, false},
+		{config.ChainDaemon{Chain: "BSC", URL: "http://binance-smart-daemon:8545", Beacon: "http://binance-smart-daemon:3500"}, true},
+		{config.ChainDaemon{Chain: "XRP", URL: "http://xrp-daemon:51234"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.daemon.Chain, func(t *testing.T) {
+			adapter, err := NewAdapter(tt.daemon)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewAdapter() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && adapter.Chain() != tt.daemon.Chain {
+				t.Errorf("expected chain %s, got %s", tt.daemon.Chain, adapter.Chain())
+			}
+		})
+	}
+}
+
+func TestCallRPCErrorRedactsCredentials(t *testing.T) {
+	stub := newDaemonStub(t, nil, nil)
+	endpoint := strings.Replace(stub.URL, "http://", "http://thorchain:secret@", 1)
+
+	var result interface{}
+	err := callRPC(stub.Client(), endpoint, "getblockchaininfo", nil, &result)
+	if err == nil {
+		t.Fatal("expected an error for an unknown method")
+	}
+	if !strings.Contains(err.Error(), "Method not found") || strings.Contains(err.Error(), "secret") {
+		t.Errorf("expected the daemon error without credentials, got %v", err)
+	}
+	if len(stub.auth) != 1 || stub.auth[0] != "thorchain" {
+		t.Errorf("expected basic auth from the url, got %q", stub.auth)
+	}
+}
@@ -0,0 +1,50 @@
+package chains
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+)
+
+// avalancheAdapter checks the avalanchego daemon, whose C-chain bifrost observes.
+type avalancheAdapter struct {
+	url    string
+	client *http.Client
+}
+
+func (a *avalancheAdapter) Chain() string {
+	return "AVAX"
+}
+
+func (a *avalancheAdapter) Status() (*ChainStatus, error) {
+	var bootstrapped struct {
+		IsBootstrapped bool `json:"isBootstrapped"`
+	}
+	if err := callRPC(a.client, a.url+"/ext/info", "info.isBootstrapped", map[string]string{"chain": "C"}, &bootstrapped); err != nil {
+		return nil, err
+	}
+	var peers struct {
+		NumPeers string `json:"numPeers"`
+	}
+	if err := callRPC(a.client, a.url+"/ext/info", "info.peers", map[string]string{}, &peers); err != nil {
+		return nil, err
+	}
+	var blockNumber string
+	if err := callRPC(a.client, a.url+"/ext/bc/C/rpc", "eth_blockNumber", nil, &blockNumber); err != nil {
+		return nil, err
+	}
+	height, err := parseHex(blockNumber)
+	if err != nil {
+		return nil, fmt.Errorf("invalid block number %q: %w", blockNumber, err)
+	}
+	numPeers, err := strconv.Atoi(peers.NumPeers)
+	if err != nil {
+		return nil, fmt.Errorf("invalid peer count %q: %w", peers.NumPeers, err)
+	}
+	return &ChainStatus{Height: height, Syncing: !bootstrapped.IsBootstrapped, Peers: numPeers}, nil
+}
+
+// Balance reads the C-chain balance of address.
+func (a *avalancheAdapter) Balance(address, router, asset string) (int64, error) {
+	return evmBalance(a.client, a.url+"/ext/bc/C/rpc", address, router, asset)
+}
@@ -0,0 +1,23 @@
+package chains
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestAvalancheAdapterStatus(t *testing.T) {
+	stub := newDaemonStub(t, map[string]interface{}{
+		"info.isBootstrapped": map[string]bool{"isBootstrapped": false},
+		"info.peers":          map[string]interface{}{"numPeers": "12", "peers": []interface{}{}},
+		"eth_blockNumber":     "0x2faf080",
+	}, nil)
+	adapter := &avalancheAdapter{url: stub.URL, client: http.DefaultClient}
+	status, err := adapter.Status()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := ChainStatus{Height: 50000000, Syncing: true, Peers: 12}
+	if *status != expected {
+		t.Errorf("expected %+v, got %+v", expected, *status)
+	}
+}
@@ -0,0 +1,147 @@
+package chains
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+)
+
+// thorchainDecimals is the precision THORChain accounts every asset in.
+const thorchainDecimals = 8
+
+// toThorchainUnits converts an amount in the given native decimals to THORChain's
+// 1e8 units, truncating any remainder.
+func toThorchainUnits(amount *big.Int, decimals int) (int64, error) {
+	scaled := new(big.Int).Set(amount)
+	if decimals > thorchainDecimals {
+		scaled.Quo(scaled, new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals-thorchainDecimals)), nil))
+	} else {
+		scaled.Mul(scaled, new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(thorchainDecimals-decimals)), nil))
+	}
+	if !scaled.IsInt64() {
+		return 0, fmt.Errorf("balance %s out of range", amount)
+	}
+	return scaled.Int64(), nil
+}
+
+// parseHexBig parses a 0x prefixed quantity or ABI encoded word as returned by EVM
+// JSON-RPC.
+func parseHexBig(quantity string) (*big.Int, error) {
+	digits := strings.TrimPrefix(quantity, "0x")
+	if digits == "" {
+		return nil, fmt.Errorf("empty quantity")
+	}
+	amount, ok := new(big.Int).SetString(digits, 16)
+	if !ok {
+		return nil, fmt.Errorf("invalid quantity %q", quantity)
+	}
+	return amount, nil
+}
+
+// evmBalance returns the balance of an EVM address in 1e8 units. Assets without a
+// contract suffix are the chain's native gas asset. Tokens are deposited into the
+// router, which keeps an allowance per vault, so like bifrost their balance is the
+// router's vaultAllowance, scaled by the token's decimals.
+func evmBalance(client *http.Client, rpcURL, address, router, asset string) (int64, error) {
+	address = strings.ToLower(address)
+	dash := strings.LastIndex(asset, "-")
+	if dash < 0 {
+		var balance string
+		if err := callRPC(client, rpcURL, "eth_getBalance", []string{address, "latest"}, &balance); err != nil {
+			return 0, err
+		}
+		wei, err := parseHexBig(balance)
+		if err != nil {
+			return 0, fmt.Errorf("invalid balance of %s: %w", address, err)
+		}
+		return toThorchainUnits(wei, 18)
+	}
+
+	token := strings.ToLower(asset[dash+1:])
+	router = strings.ToLower(router)
+	for _, a := range []string{address, token, router} {
+		if len(a) != 42 || !strings.HasPrefix(a, "0x") {
+			return 0, fmt.Errorf("invalid address %q reading %s", a, asset)
+		}
+	}
+	call := func(to, data string) (*big.Int, error) {
+		var word string
+		tx := map[string]string{"to": to, "data": data}
+		if err := callRPC(client, rpcURL, "eth_call", []interface{}{tx, "latest"}, &word); err != nil {
+			return nil, err
+		}
+		return parseHexBig(word)
+	}
+	// vaultAllowance(address,address) and decimals() selectors
+	balance, err := call(router, "0x03b6a673"+abiAddress(address)+abiAddress(token))
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s allowance of %s: %w", asset, address, err)
+	}
+	decimals, err := call(token, "0x313ce567")
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s decimals: %w", asset, err)
+	}
+	if !decimals.IsInt64() || decimals.Int64() > 36 {
+		return 0, fmt.Errorf("invalid %s decimals %s", asset, decimals)
+	}
+	return toThorchainUnits(balance, int(decimals.Int64()))
+}
+
+// abiAddress encodes a 0x prefixed address as a 32 byte ABI word.
+func abiAddress(address string) string {
+	return strings.Repeat("0", 24) + address[2:]
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// Protobuf
+////////////////////////////////////////////////////////////////////////////////
+
+// appendProtoString appends a length delimited string field to a protobuf message.
+func appendProtoString(msg []byte, field int, value string) []byte {
+	msg = binary.AppendUvarint(msg, uint64(field<<3|2))
+	msg = binary.AppendUvarint(msg, uint64(len(value)))
+	return append(msg, value...)
+}
+
+// protoField returns the first length delimited field with the given number of a
+// protobuf message, skipping the fields before it, or nil when the field is unset.
+func protoField(msg []byte, field int) ([]byte, error) {
+	for len(msg) > 0 {
+		key, n := binary.Uvarint(msg)
+		if n <= 0 {
+			return nil, fmt.Errorf("invalid protobuf key")
+		}
+		msg = msg[n:]
+
+		var size uint64
+		switch key & 7 {
+		case 0: // varint
+			if _, n = binary.Uvarint(msg); n <= 0 {
+				return nil, fmt.Errorf("invalid protobuf varint")
+			}
+			size = uint64(n)
+		case 1: // fixed64
+			size = 8
+		case 2: // length delimited
+			length, n := binary.Uvarint(msg)
+			if n <= 0 || length > uint64(len(msg)-n) {
+				return nil, fmt.Errorf("invalid protobuf length")
+			}
+			if int(key>>3) == field {
+				return msg[n : n+int(length)], nil
+			}
+			size = uint64(n) + length
+		case 5: // fixed32
+			size = 4
+		default:
+			return nil, fmt.Errorf("unsupported protobuf wire type %d", key&7)
+		}
+		if size > uint64(len(msg)) {
+			return nil, fmt.Errorf("truncated protobuf field")
+		}
+		msg = msg[size:]
+	}
+	return nil, nil
+}
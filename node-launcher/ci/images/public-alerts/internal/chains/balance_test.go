@@ -0,0 +1,48 @@
+package chains
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestToThorchainUnits(t *testing.T) {
+	wei, _ := new(big.Int).SetString("1500000000123456789", 10)
+	overflow, _ := new(big.Int).SetString("1000000000000000000000000000000", 10)
+	tests := []struct {
+		name     string
+		amount   *big.Int
+		decimals int
+		expected int64
+		wantErr  bool
+	}{
+		{"wei truncated", wei, 18, 150000000, false},
+		{"satoshis", big.NewInt(12345), 8, 12345, false},
+		{"uatom", big.NewInt(1234567), 6, 123456700, false},
+		{"overflow", overflow, 6, 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			amount, err := toThorchainUnits(tt.amount, tt.decimals)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("toThorchainUnits() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if amount != tt.expected {
+				t.Errorf("expected %d, got %d", tt.expected, amount)
+			}
+		})
+	}
+}
+
+func TestProtoField(t *testing.T) {
+	// a varint field before the wanted one is skipped
+	msg := append([]byte{0x08, 0x96, 0x01}, appendProtoString(appendProtoString(nil, 2, "uatom"), 3, "42")...)
+	if field, err := protoField(msg, 3); err != nil || string(field) != "42" {
+		t.Errorf("expected 42, got %q, %v", field, err)
+	}
+	if field, err := protoField(msg, 4); err != nil || field != nil {
+		t.Errorf("expected an unset field, got %q, %v", field, err)
+	}
+	if _, err := protoField(msg[:len(msg)-1], 3); err == nil {
+		t.Error("expected error for a truncated message")
+	}
+}
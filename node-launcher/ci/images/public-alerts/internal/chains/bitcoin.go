@@ -0,0 +1,70 @@
+package chains
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+)
+
+// noScanChains are the bitcoind forks without scantxoutset, whose balances cannot be
+// read: dogecoind 1.14 predates it.
+var noScanChains = map[string]bool{"DOGE": true}
+
+// bitcoinAdapter checks the bitcoind based daemons: bitcoin, bitcoin-cash, litecoin
+// and dogecoin.
+type bitcoinAdapter struct {
+	chain      string
+	url        string
+	client     *http.Client
+	scanClient *http.Client // for scantxoutset, which takes minutes on a large chainstate
+}
+
+func (a *bitcoinAdapter) Chain() string {
+	return a.chain
+}
+
+func (a *bitcoinAdapter) Status() (*ChainStatus, error) {
+	var info struct {
+		Blocks               int64 `json:"blocks"`
+		Headers              int64 `json:"headers"`
+		InitialBlockDownload bool  `json:"initialblockdownload"`
+	}
+	if err := callRPC(a.client, a.url, "getblockchaininfo", nil, &info); err != nil {
+		return nil, err
+	}
+	var network struct {
+		Connections int `json:"connections"`
+	}
+	if err := callRPC(a.client, a.url, "getnetworkinfo", nil, &network); err != nil {
+		return nil, err
+	}
+	return &ChainStatus{
+		Height: info.Blocks,
+		// a new header briefly leads the validated blocks, more than one is a sync
+		Syncing: info.InitialBlockDownload || info.Headers-info.Blocks > 1,
+		Peers:   network.Connections,
+	}, nil
+}
+
+// Balance scans the UTXO set for the outputs of address. The daemon needs to
+// support scantxoutset and runs a single scan at a time.
+func (a *bitcoinAdapter) Balance(address, _, asset string) (int64, error) {
+	if asset != a.chain+"."+a.chain {
+		return 0, fmt.Errorf("unsupported asset %s on %s", asset, a.chain)
+	}
+	if noScanChains[a.chain] {
+		return 0, fmt.Errorf("the %s daemon does not support scantxoutset", a.chain)
+	}
+	var scan struct {
+		Success     bool    `json:"success"`
+		TotalAmount float64 `json:"total_amount"`
+	}
+	params := []interface{}{"start", []string{"addr(" + address + ")"}}
+	if err := callRPC(a.scanClient, a.url, "scantxoutset", params, &scan); err != nil {
+		return 0, err
+	}
+	if !scan.Success {
+		return 0, fmt.Errorf("scan of %s on %s did not complete", address, a.chain)
+	}
+	return int64(math.Round(scan.TotalAmount * 1e8)), nil
+}
@@ -0,0 +1,65 @@
+package chains
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestBitcoinAdapterStatus(t *testing.T) {
+	tests := []struct {
+		name     string
+		info     map[string]interface{}
+		expected ChainStatus
+	}{
+		{"synced", map[string]interface{}{"blocks": 850000, "headers": 850000, "initialblockdownload": false}, ChainStatus{Height: 850000, Peers: 8}},
+		{"new header", map[string]interface{}{"blocks": 850000, "headers": 850001, "initialblockdownload": false}, ChainStatus{Height: 850000, Peers: 8}},
+		{"initial block download", map[string]interface{}{"blocks": 500000, "headers": 850000, "initialblockdownload": true}, ChainStatus{Height: 500000, Syncing: true, Peers: 8}},
+		{"dogecoin without ibd flag", map[string]interface{}{"blocks": 5000000, "headers": 5000100}, ChainStatus{Height: 5000000, Syncing: true, Peers: 8}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stub := newDaemonStub(t, map[string]interface{}{
+				"getblockchaininfo": tt.info,
+				"getnetworkinfo":    map[string]interface{}{"connections": 8},
+			}, nil)
+			adapter := &bitcoinAdapter{chain: "BTC", url: stub.URL, client: http.DefaultClient}
+			status, err := adapter.Status()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if *status != tt.expected {
+				t.Errorf("expected %+v, got %+v", tt.expected, *status)
+			}
+		})
+	}
+}
+
+func TestBitcoinAdapterBalance(t *testing.T) {
+	stub := newDaemonStub(t, map[string]interface{}{
+		"scantxoutset": func(params interface{}) interface{} {
+			descriptors := params.([]interface{})[1].([]interface{})
+			if descriptors[0] != "addr(bc1qvault)" {
+				return map[string]interface{}{"success": true, "total_amount": 0}
+			}
+			return map[string]interface{}{"success": true, "total_amount": 12.34567891}
+		},
+	}, nil)
+	adapter := &bitcoinAdapter{chain: "BTC", url: stub.URL, client: http.DefaultClient, scanClient: http.DefaultClient}
+
+	balance, err := adapter.Balance("bc1qvault", "", "BTC.BTC")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if balance != 1234567891 {
+		t.Errorf("expected 1234567891, got %d", balance)
+	}
+	if _, err := adapter.Balance("bc1qvault", "", "LTC.LTC"); err == nil {
+		t.Error("expected error for an asset of another chain")
+	}
+
+	// dogecoind has no scantxoutset
+	doge := &bitcoinAdapter{chain: "DOGE", url: stub.URL, client: http.DefaultClient, scanClient: http.DefaultClient}
+	if _, err := doge.Balance("DDogeVault", "", "DOGE.DOGE"); err == nil || ReadsBalances("DOGE") {
+		t.Error("expected DOGE balances to be unsupported")
+	}
+}
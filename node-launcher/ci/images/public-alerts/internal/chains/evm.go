@@ -0,0 +1,62 @@
+package chains
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// evmAdapter checks the geth based daemons, and for ethereum the sync state of the
+// prysm beacon client when its API is configured.
+type evmAdapter struct {
+	chain  string
+	url    string
+	beacon string
+	client *http.Client
+}
+
+func (a *evmAdapter) Chain() string {
+	return a.chain
+}
+
+func (a *evmAdapter) Status() (*ChainStatus, error) {
+	// eth_syncing returns false once synced, or an object with the sync progress
+	var syncing interface{}
+	if err := callRPC(a.client, a.url, "eth_syncing", nil, &syncing); err != nil {
+		return nil, err
+	}
+	status := &ChainStatus{Syncing: syncing != false}
+
+	var blockNumber, peerCount string
+	if err := callRPC(a.client, a.url, "eth_blockNumber", nil, &blockNumber); err != nil {
+		return nil, err
+	}
+	if err := callRPC(a.client, a.url, "net_peerCount", nil, &peerCount); err != nil {
+		return nil, err
+	}
+	height, err := parseHex(blockNumber)
+	if err != nil {
+		return nil, fmt.Errorf("invalid block number %q: %w", blockNumber, err)
+	}
+	peers, err := parseHex(peerCount)
+	if err != nil {
+		return nil, fmt.Errorf("invalid peer count %q: %w", peerCount, err)
+	}
+	status.Height, status.Peers = height, int(peers)
+
+	if a.beacon != "" {
+		var beacon struct {
+			Data struct {
+				IsSyncing bool `json:"is_syncing"`
+			} `json:"data"`
+		}
+		if err := getJSON(a.client, a.beacon+"/eth/v1/node/syncing", &beacon); err != nil {
+			return nil, err
+		}
+		status.Syncing = status.Syncing || beacon.Data.IsSyncing
+	}
+	return status, nil
+}
+
+func (a *evmAdapter) Balance(address, router, asset string) (int64, error) {
+	return evmBalance(a.client, a.url, address, router, asset)
+}
@@ -0,0 +1,96 @@
+package chains
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestEVMAdapterStatus(t *testing.T) {
+	tests := []struct {
+		name          string
+		syncing       interface{}
+		beaconSyncing *bool
+		expected      ChainStatus
+	}{
+		{"synced", false, nil, ChainStatus{Height: 20000000, Peers: 50}},
+		{"execution syncing", map[string]string{"currentBlock": "0x1312d00", "highestBlock": "0x1312e00"}, nil, ChainStatus{Height: 20000000, Syncing: true, Peers: 50}},
+		{"beacon synced", false, new(bool), ChainStatus{Height: 20000000, Peers: 50}},
+		{"beacon syncing", false, func() *bool { b := true; return &b }(), ChainStatus{Height: 20000000, Syncing: true, Peers: 50}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			paths := map[string]string{}
+			if tt.beaconSyncing != nil {
+				body := `{"data":{"head_slot":"9000000","sync_distance":"0","is_syncing":false}}`
+				if *tt.beaconSyncing {
+					body = `{"data":{"head_slot":"8000000","sync_distance":"1000000","is_syncing":true}}`
+				}
+				paths["/eth/v1/node/syncing"] = body
+			}
+			stub := newDaemonStub(t, map[string]interface{}{
+				"eth_syncing":     tt.syncing,
+				"eth_blockNumber": "0x1312d00",
+				"net_peerCount":   "0x32",
+			}, paths)
+
+			adapter := &evmAdapter{chain: "ETH", url: stub.URL, client: http.DefaultClient}
+			if tt.beaconSyncing != nil {
+				adapter.beacon = stub.URL
+			}
+			status, err := adapter.Status()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if *status != tt.expected {
+				t.Errorf("expected %+v, got %+v", tt.expected, *status)
+			}
+		})
+	}
+}
+
+func TestEVMAdapterBalance(t *testing.T) {
+	const (
+		vault  = "0x1111111111111111111111111111111111111111"
+		router = "0xd37bbe5744d730a1d98d8dc97c42f0ca46ad7146"
+		usdc   = "0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48"
+	)
+	stub := newDaemonStub(t, map[string]interface{}{
+		"eth_getBalance": "0x14d1120d7b160000", // 1.5 ETH
+		"eth_call": func(params interface{}) interface{} {
+			tx := params.([]interface{})[0].(map[string]interface{})
+			data := tx["data"].(string)
+			switch {
+			case tx["to"] == router && data == "0x03b6a673"+strings.Repeat("0", 24)+vault[2:]+strings.Repeat("0", 24)+usdc[2:]:
+				return "0x" + strings.Repeat("0", 58) + "2625a0" // 2.5 USDC
+			case tx["to"] == usdc && data == "0x313ce567":
+				return "0x" + strings.Repeat("0", 63) + "6"
+			}
+			return "0x"
+		},
+	}, nil)
+	adapter := &evmAdapter{chain: "ETH", url: stub.URL, client: http.DefaultClient}
+
+	tests := []struct {
+		asset    string
+		router   string
+		expected int64
+		wantErr  bool
+	}{
+		{"ETH.ETH", "", 150000000, false},
+		{"ETH.USDC-0XA0B86991C6218B36C1D19D4A2E9EB0CE3606EB48", strings.ToUpper(router[2:]), 0, true},
+		{"ETH.USDC-0XA0B86991C6218B36C1D19D4A2E9EB0CE3606EB48", router, 250000000, false},
+		{"ETH.USDT-0XDAC17F958D2EE523A2206206994597C13D831EC7", router, 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.asset, func(t *testing.T) {
+			balance, err := adapter.Balance(strings.ToUpper(vault), tt.router, tt.asset)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Balance() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if balance != tt.expected {
+				t.Errorf("expected %d, got %d", tt.expected, balance)
+			}
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package chains
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strconv"
+)
+
+// gaiaAdapter checks the gaia daemon through its Tendermint RPC.
+type gaiaAdapter struct {
+	url    string
+	client *http.Client
+}
+
+func (a *gaiaAdapter) Chain() string {
+	return "GAIA"
+}
+
+func (a *gaiaAdapter) Status() (*ChainStatus, error) {
+	var status struct {
+		Result struct {
+			SyncInfo struct {
+				LatestBlockHeight string `json:"latest_block_height"`
+				CatchingUp        bool   `json:"catching_up"`
+			} `json:"sync_info"`
+		} `json:"result"`
+	}
+	if err := getJSON(a.client, a.url+"/status", &status); err != nil {
+		return nil, err
+	}
+	var netInfo struct {
+		Result struct {
+			NPeers string `json:"n_peers"`
+		} `json:"result"`
+	}
+	if err := getJSON(a.client, a.url+"/net_info", &netInfo); err != nil {
+		return nil, err
+	}
+	height, err := strconv.ParseInt(status.Result.SyncInfo.LatestBlockHeight, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid block height %q: %w", status.Result.SyncInfo.LatestBlockHeight, err)
+	}
+	peers, err := strconv.Atoi(netInfo.Result.NPeers)
+	if err != nil {
+		return nil, fmt.Errorf("invalid peer count %q: %w", netInfo.Result.NPeers, err)
+	}
+	return &ChainStatus{Height: height, Syncing: status.Result.SyncInfo.CatchingUp, Peers: peers}, nil
+}
+
+// Balance queries the bank module through abci_query. The Tendermint RPC only speaks
+// protobuf, so the QueryBalanceRequest and QueryBalanceResponse are coded by hand.
+func (a *gaiaAdapter) Balance(address, _, asset string) (int64, error) {
+	if asset != "GAIA.ATOM" {
+		return 0, fmt.Errorf("unsupported asset %s on GAIA", asset)
+	}
+	request := appendProtoString(appendProtoString(nil, 1, address), 2, "uatom")
+	params := map[string]string{
+		"path": "/cosmos.bank.v1beta1.Query/Balance",
+		"data": hex.EncodeToString(request),
+	}
+	var query struct {
+		Response struct {
+			Code  int    `json:"code"`
+			Log   string `json:"log"`
+			Value []byte `json:"value"`
+		} `json:"response"`
+	}
+	if err := callRPC(a.client, a.url, "abci_query", params, &query); err != nil {
+		return 0, err
+	}
+	if query.Response.Code != 0 {
+		return 0, fmt.Errorf("error querying balance of %s: %s (%d)", address, query.Response.Log, query.Response.Code)
+	}
+
+	coin, err := protoField(query.Response.Value, 1)
+	if err != nil {
+		return 0, fmt.Errorf("invalid balance of %s: %w", address, err)
+	}
+	amount, err := protoField(coin, 2)
+	if err != nil {
+		return 0, fmt.Errorf("invalid balance of %s: %w", address, err)
+	}
+	// an address without uatom has no amount
+	if len(amount) == 0 {
+		return 0, nil
+	}
+	uatom, ok := new(big.Int).SetString(string(amount), 10)
+	if !ok {
+		return 0, fmt.Errorf("invalid balance %q of %s", amount, address)
+	}
+	return toThorchainUnits(uatom, 6)
+}
@@ -0,0 +1,66 @@
+package chains
+
+import (
+	"encoding/hex"
+	"net/http"
+	"testing"
+)
+
+func TestGaiaAdapterStatus(t *testing.T) {
+	stub := newDaemonStub(t, nil, map[string]string{
+		"/status":   `{"jsonrpc":"2.0","id":-1,"result":{"sync_info":{"latest_block_height":"21000000","catching_up":true}}}`,
+		"/net_info": `{"jsonrpc":"2.0","id":-1,"result":{"listening":true,"n_peers":"0"}}`,
+	})
+	adapter := &gaiaAdapter{url: stub.URL, client: http.DefaultClient}
+	status, err := adapter.Status()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := ChainStatus{Height: 21000000, Syncing: true, Peers: 0}
+	if *status != expected {
+		t.Errorf("expected %+v, got %+v", expected, *status)
+	}
+
+	delete(stub.paths, "/net_info")
+	if _, err := adapter.Status(); err == nil {
+		t.Error("expected error when net_info is unavailable")
+	}
+}
+
+func TestGaiaAdapterBalance(t *testing.T) {
+	coin := appendProtoString(appendProtoString(nil, 1, "uatom"), 2, "1234567")
+	stub := newDaemonStub(t, map[string]interface{}{
+		"abci_query": func(params interface{}) interface{} {
+			query := params.(map[string]interface{})
+			request, _ := hex.DecodeString(query["data"].(string))
+			address, err := protoField(request, 1)
+			if err != nil || query["path"] != "/cosmos.bank.v1beta1.Query/Balance" {
+				return map[string]interface{}{"response": map[string]interface{}{"code": 1, "log": "bad request"}}
+			}
+			if string(address) != "cosmos1vault" {
+				// the balance of an unknown address is an empty coin
+				return map[string]interface{}{"response": map[string]interface{}{"code": 0}}
+			}
+			value := appendProtoString(nil, 1, string(coin))
+			return map[string]interface{}{"response": map[string]interface{}{"code": 0, "value": value}}
+		},
+	}, nil)
+	adapter := &gaiaAdapter{url: stub.URL, client: http.DefaultClient}
+
+	tests := []struct {
+		address  string
+		expected int64
+	}{
+		{"cosmos1vault", 123456700},
+		{"cosmos1empty", 0},
+	}
+	for _, tt := range tests {
+		balance, err := adapter.Balance(tt.address, "", "GAIA.ATOM")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if balance != tt.expected {
+			t.Errorf("expected %d for %s, got %d", tt.expected, tt.address, balance)
+		}
+	}
+}
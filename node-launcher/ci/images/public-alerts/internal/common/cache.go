@@ -0,0 +1,94 @@
+package common
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// TTLCache caches values per key for a fixed duration. Concurrent lookups of a
+// missing key share a single fetch (singleflight), so monitors polling at the
+// same time don't each hit the upstream API. Errors are never cached.
+type TTLCache[K comparable, V any] struct {
+	ttl time.Duration
+	now func() time.Time
+
+	mu       sync.Mutex
+	entries  map[K]cacheEntry[V]
+	inflight map[K]*cacheCall[V]
+}
+
+type cacheEntry[V any] struct {
+	value   V
+	expires time.Time
+}
+
+type cacheCall[V any] struct {
+	done  chan struct{}
+	value V
+	err   error
+}
+
+// NewTTLCache creates a cache whose entries expire ttl after being fetched.
+func NewTTLCache[K comparable, V any](ttl time.Duration) *TTLCache[K, V] {
+	return &TTLCache[K, V]{
+		ttl:      ttl,
+		now:      time.Now,
+		entries:  make(map[K]cacheEntry[V]),
+		inflight: make(map[K]*cacheCall[V]),
+	}
+}
+
+// Get returns the cached value for key, calling fetch to populate it when the
+// entry is missing or expired. A panicking fetch is returned as an error to every
+// caller waiting on it.
+func (c *TTLCache[K, V]) Get(key K, fetch func() (V, error)) (V, error) {
+	c.mu.Lock()
+	if entry, ok := c.entries[key]; ok && c.now().Before(entry.expires) {
+		c.mu.Unlock()
+		return entry.value, nil
+	}
+	if call, ok := c.inflight[key]; ok {
+		c.mu.Unlock()
+		<-call.done
+		return call.value, call.err
+	}
+	call := &cacheCall[V]{done: make(chan struct{})}
+	c.inflight[key] = call
+	c.mu.Unlock()
+
+	call.value, call.err = safeFetch(fetch)
+
+	c.mu.Lock()
+	delete(c.inflight, key)
+	if call.err == nil {
+		c.entries[key] = cacheEntry[V]{value: call.value, expires: c.now().Add(c.ttl)}
+	}
+	c.evictExpired()
+	c.mu.Unlock()
+	close(call.done)
+
+	return call.value, call.err
+}
+
+// safeFetch calls fetch, turning a panic into an error so the in-flight call is
+// always completed.
+func safeFetch[V any](fetch func() (V, error)) (value V, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("fetch panicked: %v", r)
+		}
+	}()
+	return fetch()
+}
+
+// evictExpired drops expired entries so keys that are never requested again
+// (e.g. past heights) don't accumulate. Must be called with the lock held.
+func (c *TTLCache[K, V]) evictExpired() {
+	now := c.now()
+	for key, entry := range c.entries {
+		if !now.Before(entry.expires) {
+			delete(c.entries, key)
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package common
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestTTLCacheExpiry(t *testing.T) {
+	now := time.Now()
+	cache := NewTTLCache[string, int](time.Minute)
+	cache.now = func() time.Time { return now }
+
+	fetches := 0
+	fetch := func() (int, error) {
+		fetches++
+		return fetches, nil
+	}
+
+	if v, _ := cache.Get("nodes", fetch); v != 1 {
+		t.Errorf("expected first fetch, got %d", v)
+	}
+	if v, _ := cache.Get("nodes", fetch); v != 1 {
+		t.Errorf("expected cached value, got %d", v)
+	}
+	if v, _ := cache.Get("mimir", fetch); v != 2 {
+		t.Errorf("expected keys to be cached separately, got %d", v)
+	}
+
+	now = now.Add(time.Minute)
+	if v, _ := cache.Get("nodes", fetch); v != 3 {
+		t.Errorf("expected refetch after expiry, got %d", v)
+	}
+	if _, ok := cache.entries["mimir"]; ok {
+		t.Error("expected expired entries to be evicted")
+	}
+}
+
+func TestTTLCacheErrorsNotCached(t *testing.T) {
+	cache := NewTTLCache[string, int](time.Minute)
+
+	if _, err := cache.Get("nodes", func() (int, error) { return 0, errors.New("unavailable") }); err == nil {
+		t.Fatal("expected fetch error to be returned")
+	}
+	v, err := cache.Get("nodes", func() (int, error) { return 42, nil })
+	if err != nil || v != 42 {
+		t.Errorf("expected refetch after error, got %d, %v", v, err)
+	}
+}
+
+func TestTTLCachePanicReleasesWaiters(t *testing.T) {
+	cache := NewTTLCache[string, int](time.Minute)
+
+	started, release := make(chan struct{}), make(chan struct{})
+	results := make(chan error, 2)
+	go func() {
+		_, err := cache.Get("nodes", func() (int, error) {
+			close(started)
+			<-release
+			panic("boom")
+		})
+		results <- err
+	}()
+	<-started
+	go func() {
+		_, err := cache.Get("nodes", func() (int, error) { return 42, nil })
+		results <- err
+	}()
+	// let the second lookup wait on the in-flight call before it panics
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+
+	for i := 0; i < 2; i++ {
+		select {
+		case err := <-results:
+			if err == nil {
+				t.Error("expected the panic to be returned as an error")
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("lookup blocked after a panicking fetch")
+		}
+	}
+	if v, err := cache.Get("nodes", func() (int, error) { return 42, nil }); err != nil || v != 42 {
+		t.Errorf("expected refetch after the panic, got %d, %v", v, err)
+	}
+}
+
+func TestTTLCacheSingleflight(t *testing.T) {
+	cache := NewTTLCache[string, int](time.Minute)
+
+	var fetches atomic.Int32
+	release := make(chan struct{})
+	fetch := func() (int, error) {
+		fetches.Add(1)
+		<-release
+		return 7, nil
+	}
+
+	var wg sync.WaitGroup
+	results := make([]int, 10)
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i], _ = cache.Get("pools", fetch)
+		}(i)
+	}
+
+	// wait for one fetch to start before letting it complete
+	for fetches.Load() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if fetches.Load() != 1 {
+		t.Errorf("expected concurrent lookups to share one fetch, got %d", fetches.Load())
+	}
+	for i, v := range results {
+		if v != 7 {
+			t.Errorf("lookup %d: expected 7, got %d", i, v)
+		}
+	}
+}
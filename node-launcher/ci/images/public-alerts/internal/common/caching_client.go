@@ -0,0 +1,98 @@
+package common
+
+import (
+	"maps"
+	"slices"
+	"time"
+
+	openapi "gitlab.com/thorchain/thornode/openapi/gen"
+)
+
+// cachingClient wraps a ThornodeDataFetcher and shares the results of commonly
+// polled queries between monitors for the cache TTL. The latest height is cached
+// as well, so monitors checking within the same window pin the same height and
+// hit the same cache entries. Every caller gets a shallow copy of the cached slices
+// and maps, so sorting or filtering them does not affect the other monitors; the
+// pointed-to fields of the elements are still shared and must not be modified.
+type cachingClient struct {
+	ThornodeDataFetcher // uncached queries pass through
+	*queryCaches
+	height int // queries are pinned to this height when non-zero
+}
+
+// queryCaches holds the per-query caches, keyed by pinned height (0 for latest).
+type queryCaches struct {
+	latestHeight *TTLCache[int, int]
+	nodes        *TTLCache[int, []openapi.Node]
+	pools        *TTLCache[int, []openapi.Pool]
+	mimir        *TTLCache[int, map[string]int64]
+	adminMimir   *TTLCache[int, map[string]int64]
+	mimirVotes   *TTLCache[int, []openapi.MimirVote]
+	inbound      *TTLCache[int, []openapi.InboundAddress]
+}
+
+// NewCachingClient wraps client with caches sharing its results for ttl.
+func NewCachingClient(client ThornodeDataFetcher, ttl time.Duration) ThornodeDataFetcher {
+	return newCachingClient(client, ttl)
+}
+
+func newCachingClient(client ThornodeDataFetcher, ttl time.Duration) *cachingClient {
+	return &cachingClient{
+		ThornodeDataFetcher: client,
+		queryCaches: &queryCaches{
+			latestHeight: NewTTLCache[int, int](ttl),
+			nodes:        NewTTLCache[int, []openapi.Node](ttl),
+			pools:        NewTTLCache[int, []openapi.Pool](ttl),
+			mimir:        NewTTLCache[int, map[string]int64](ttl),
+			adminMimir:   NewTTLCache[int, map[string]int64](ttl),
+			mimirVotes:   NewTTLCache[int, []openapi.MimirVote](ttl),
+			inbound:      NewTTLCache[int, []openapi.InboundAddress](ttl),
+		},
+	}
+}
+
+// AtHeight returns a view of the client pinned to height, sharing its caches.
+func (c *cachingClient) AtHeight(height int) ThornodeDataFetcher {
+	return &cachingClient{
+		ThornodeDataFetcher: c.ThornodeDataFetcher.AtHeight(height),
+		queryCaches:         c.queryCaches,
+		height:              height,
+	}
+}
+
+func (c *cachingClient) GetLatestHeight() (int, error) {
+	if c.height > 0 {
+		return c.height, nil
+	}
+	return c.latestHeight.Get(0, c.ThornodeDataFetcher.GetLatestHeight)
+}
+
+func (c *cachingClient) GetNodes() ([]openapi.Node, error) {
+	values, err := c.nodes.Get(c.height, c.ThornodeDataFetcher.GetNodes)
+	return slices.Clone(values), err
+}
+
+func (c *cachingClient) GetPools() ([]openapi.Pool, error) {
+	values, err := c.pools.Get(c.height, c.ThornodeDataFetcher.GetPools)
+	return slices.Clone(values), err
+}
+
+func (c *cachingClient) GetMimir() (map[string]int64, error) {
+	values, err := c.mimir.Get(c.height, c.ThornodeDataFetcher.GetMimir)
+	return maps.Clone(values), err
+}
+
+func (c *cachingClient) GetAdminMimir() (map[string]int64, error) {
+	values, err := c.adminMimir.Get(c.height, c.ThornodeDataFetcher.GetAdminMimir)
+	return maps.Clone(values), err
+}
+
+func (c *cachingClient) GetMimirVotes() ([]openapi.MimirVote, error) {
+	values, err := c.mimirVotes.Get(c.height, c.ThornodeDataFetcher.GetMimirVotes)
+	return slices.Clone(values), err
+}
+
+func (c *cachingClient) GetInboundAddresses() ([]openapi.InboundAddress, error) {
+	values, err := c.inbound.Get(c.height, c.ThornodeDataFetcher.GetInboundAddresses)
+	return slices.Clone(values), err
+}
@@ -0,0 +1,78 @@
+package common
+
+import (
+	"testing"
+	"time"
+
+	openapi "gitlab.com/thorchain/thornode/openapi/gen"
+)
+
+// countingClient counts the queries reaching the wrapped fake.
+type countingClient struct {
+	*FakeClient
+	heights, nodes int
+}
+
+func (c *countingClient) AtHeight(height int) ThornodeDataFetcher {
+	c.FakeClient.AtHeight(height)
+	return c
+}
+
+func (c *countingClient) GetLatestHeight() (int, error) {
+	c.heights++
+	return c.FakeClient.GetLatestHeight()
+}
+
+func (c *countingClient) GetNodes() ([]openapi.Node, error) {
+	c.nodes++
+	return c.FakeClient.GetNodes()
+}
+
+func TestCachingClientSharesPinnedQueries(t *testing.T) {
+	inner := &countingClient{FakeClient: NewFakeClient()}
+	inner.Height = 100
+	inner.Nodes = []openapi.Node{{NodeAddress: "thor1a"}}
+	client := newCachingClient(inner, time.Minute)
+
+	// two monitors checking in the same window pin the same height and share queries
+	for i := 0; i < 2; i++ {
+		pinned, height, err := PinLatestHeight(client)
+		if err != nil || height != 100 {
+			t.Fatalf("expected height 100, got %d (%v)", height, err)
+		}
+		nodes, err := pinned.GetNodes()
+		if err != nil || len(nodes) != 1 {
+			t.Fatalf("unexpected nodes %v (%v)", nodes, err)
+		}
+	}
+	if inner.heights != 1 || inner.nodes != 1 {
+		t.Errorf("expected one height and one nodes query, got %d and %d", inner.heights, inner.nodes)
+	}
+
+	// a different height is a different cache entry
+	if _, err := client.AtHeight(99).GetNodes(); err != nil {
+		t.Fatal(err)
+	}
+	if inner.nodes != 2 {
+		t.Errorf("expected a query for the new height, got %d", inner.nodes)
+	}
+}
+
+func TestCachingClientReturnsCopies(t *testing.T) {
+	inner := NewFakeClient()
+	inner.Pools = []openapi.Pool{{Asset: "ETH.ETH"}, {Asset: "BTC.BTC"}}
+	inner.Mimir = map[string]int64{"HALTTRADING": 0}
+	client := newCachingClient(inner, time.Minute)
+
+	pools, _ := client.GetPools()
+	pools[0], pools[1] = pools[1], pools[0]
+	mimir, _ := client.GetMimir()
+	mimir["HALTTRADING"] = 1
+
+	if pools, _ := client.GetPools(); pools[0].Asset != "ETH.ETH" {
+		t.Errorf("expected the cached pools to be unchanged, got %v", pools)
+	}
+	if mimir, _ := client.GetMimir(); mimir["HALTTRADING"] != 0 {
+		t.Errorf("expected the cached mimir to be unchanged, got %v", mimir)
+	}
+}
@@ -0,0 +1,210 @@
+package common
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	openapi "gitlab.com/thorchain/thornode/openapi/gen"
+)
+
+// providerCooldown is how long a provider is skipped after its first failure.
+// Consecutive failures back off exponentially up to maxProviderBackoff.
+const (
+	providerCooldown   = 30 * time.Second
+	maxProviderBackoff = 8
+)
+
+type providerState struct {
+	ProviderClient
+	failures  int
+	downUntil time.Time
+}
+
+// failoverClient implements ThornodeDataFetcher over several providers. Each request
+// goes to the first healthy provider in order of preference and fails over to the
+// next one on error. Providers that fail are skipped until their cooldown expires.
+type failoverClient struct {
+	*providerPool
+	height int // queries are pinned to this height when non-zero
+}
+
+// providerPool holds provider health, shared by a client and its pinned views.
+type providerPool struct {
+	mu        sync.Mutex
+	providers []*providerState
+	now       func() time.Time
+}
+
+func newFailoverClient(providers []ProviderClient) *failoverClient {
+	states := make([]*providerState, 0, len(providers))
+	for _, p := range providers {
+		states = append(states, &providerState{ProviderClient: p})
+	}
+	return &failoverClient{providerPool: &providerPool{providers: states, now: time.Now}}
+}
+
+// AtHeight returns a view of the client with every provider query pinned to height.
+func (c *failoverClient) AtHeight(height int) ThornodeDataFetcher {
+	return &failoverClient{providerPool: c.providerPool, height: height}
+}
+
+// candidates returns the providers in order of preference, healthy providers first.
+// Unhealthy providers are still returned last so a request is attempted even when
+// every provider is cooling down.
+func (c *providerPool) candidates() []*providerState {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var healthy, down []*providerState
+	for _, p := range c.providers {
+		if c.now().Before(p.downUntil) {
+			down = append(down, p)
+		} else {
+			healthy = append(healthy, p)
+		}
+	}
+	return append(healthy, down...)
+}
+
+func (c *providerPool) record(p *providerState, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err == nil {
+		if p.failures > 0 {
+			log.Info().Str("provider", p.Name).Msg("thornode provider recovered")
+		}
+		p.failures = 0
+		p.downUntil = time.Time{}
+		return
+	}
+
+	p.failures++
+	backoff := providerCooldown * time.Duration(min(1<<(p.failures-1), maxProviderBackoff))
+	p.downUntil = c.now().Add(backoff)
+	log.Warn().Err(err).Str("provider", p.Name).Int("failures", p.failures).Msgf("thornode provider unhealthy, skipping for %s", backoff)
+}
+
+// isProviderFailure reports whether err reflects an unhealthy provider, as opposed
+// to a request that would fail against any provider (e.g. an unknown tx hash).
+func isProviderFailure(err error) bool {
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.StatusCode >= 500 || statusErr.StatusCode == http.StatusTooManyRequests
+	}
+	return true
+}
+
+// withFailover runs call against each candidate provider until one succeeds.
+func withFailover[T any](c *failoverClient, call func(ThornodeDataFetcher) (T, error)) (T, error) {
+	var zero T
+	var errs []error
+	for _, p := range c.candidates() {
+		fetcher := p.ThornodeDataFetcher
+		if c.height > 0 {
+			fetcher = fetcher.AtHeight(c.height)
+		}
+		result, err := call(fetcher)
+		if err == nil {
+			c.record(p, nil)
+			return result, nil
+		}
+		if !isProviderFailure(err) {
+			return zero, err
+		}
+		c.record(p, err)
+		errs = append(errs, fmt.Errorf("%s: %w", p.Name, err))
+	}
+	if len(errs) == 0 {
+		return zero, fmt.Errorf("no thornode providers configured")
+	}
+	return zero, fmt.Errorf("all thornode providers failed: %w", errors.Join(errs...))
+}
+
+func (c *failoverClient) GetLatestHeight() (int, error) {
+	return withFailover(c, func(f ThornodeDataFetcher) (int, error) { return f.GetLatestHeight() })
+}
+
+func (c *failoverClient) GetNodes() ([]openapi.Node, error) {
+	return withFailover(c, func(f ThornodeDataFetcher) ([]openapi.Node, error) { return f.GetNodes() })
+}
+
+func (c *failoverClient) GetInvariants() ([]string, error) {
+	return withFailover(c, func(f ThornodeDataFetcher) ([]string, error) { return f.GetInvariants() })
+}
+
+func (c *failoverClient) GetInvariant(invariant string) (*openapi.InvariantResponse, error) {
+	return withFailover(c, func(f ThornodeDataFetcher) (*openapi.InvariantResponse, error) { return f.GetInvariant(invariant) })
+}
+
+func (c *failoverClient) GetOutboundQueue() ([]openapi.TxOutItem, error) {
+	return withFailover(c, func(f ThornodeDataFetcher) ([]openapi.TxOutItem, error) { return f.GetOutboundQueue() })
+}
+
+func (c *failoverClient) GetTxDetails(hash string) (*openapi.TxDetailsResponse, error) {
+	return withFailover(c, func(f ThornodeDataFetcher) (*openapi.TxDetailsResponse, error) { return f.GetTxDetails(hash) })
+}
+
+func (c *failoverClient) GetVaults() ([]openapi.Vault, error) {
+	return withFailover(c, func(f ThornodeDataFetcher) ([]openapi.Vault, error) { return f.GetVaults() })
+}
+
+func (c *failoverClient) GetPools() ([]openapi.Pool, error) {
+	return withFailover(c, func(f ThornodeDataFetcher) ([]openapi.Pool, error) { return f.GetPools() })
+}
+
+func (c *failoverClient) GetSavers(asset string) ([]openapi.Saver, error) {
+	return withFailover(c, func(f ThornodeDataFetcher) ([]openapi.Saver, error) { return f.GetSavers(asset) })
+}
+
+func (c *failoverClient) GetMimir() (map[string]int64, error) {
+	return withFailover(c, func(f ThornodeDataFetcher) (map[string]int64, error) { return f.GetMimir() })
+}
+
+func (c *failoverClient) GetAdminMimir() (map[string]int64, error) {
+	return withFailover(c, func(f ThornodeDataFetcher) (map[string]int64, error) { return f.GetAdminMimir() })
+}
+
+func (c *failoverClient) GetMimirVotes() ([]openapi.MimirVote, error) {
+	return withFailover(c, func(f ThornodeDataFetcher) ([]openapi.MimirVote, error) { return f.GetMimirVotes() })
+}
+
+func (c *failoverClient) GetInboundAddresses() ([]openapi.InboundAddress, error) {
+	return withFailover(c, func(f ThornodeDataFetcher) ([]openapi.InboundAddress, error) { return f.GetInboundAddresses() })
+}
+
+func (c *failoverClient) GetCommit(height int) (*BlockCommit, error) {
+	return withFailover(c, func(f ThornodeDataFetcher) (*BlockCommit, error) { return f.GetCommit(height) })
+}
+
+func (c *failoverClient) GetVersion() (*openapi.VersionResponse, error) {
+	return withFailover(c, func(f ThornodeDataFetcher) (*openapi.VersionResponse, error) { return f.GetVersion() })
+}
+
+func (c *failoverClient) GetUpgradeProposals() ([]UpgradeProposal, error) {
+	return withFailover(c, func(f ThornodeDataFetcher) ([]UpgradeProposal, error) { return f.GetUpgradeProposals() })
+}
+
+func (c *failoverClient) GetNetwork() (*openapi.NetworkResponse, error) {
+	return withFailover(c, func(f ThornodeDataFetcher) (*openapi.NetworkResponse, error) { return f.GetNetwork() })
+}
+
+func (c *failoverClient) GetRuneSupply() (int64, error) {
+	return withFailover(c, func(f ThornodeDataFetcher) (int64, error) { return f.GetRuneSupply() })
+}
+
+func (c *failoverClient) GetRuneBalance(address string) (int64, error) {
+	return withFailover(c, func(f ThornodeDataFetcher) (int64, error) { return f.GetRuneBalance(address) })
+}
+
+func (c *failoverClient) GetScheduledQueue() ([]openapi.TxOutItem, error) {
+	return withFailover(c, func(f ThornodeDataFetcher) ([]openapi.TxOutItem, error) { return f.GetScheduledQueue() })
+}
+
+func (c *failoverClient) GetQueue() (*openapi.QueueResponse, error) {
+	return withFailover(c, func(f ThornodeDataFetcher) (*openapi.QueueResponse, error) { return f.GetQueue() })
+}
@@ -0,0 +1,79 @@
+package common
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	openapi "gitlab.com/thorchain/thornode/openapi/gen"
+)
+
+func TestFailoverClient(t *testing.T) {
+	primary, secondary := NewFakeClient(), NewFakeClient()
+	primary.Height, secondary.Height = 100, 99
+	primary.Err = errors.New("connection refused")
+
+	now := time.Now()
+	client := newFailoverClient([]ProviderClient{
+		{Name: "primary", ThornodeDataFetcher: primary},
+		{Name: "secondary", ThornodeDataFetcher: secondary},
+	})
+	client.now = func() time.Time { return now }
+
+	// a failing provider is skipped in favour of the next one
+	height, err := client.GetLatestHeight()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if height != 99 {
+		t.Errorf("expected height from secondary provider, got %d", height)
+	}
+
+	// the failed provider is not retried during its cooldown even once it recovers
+	primary.Err = nil
+	if height, _ = client.GetLatestHeight(); height != 99 {
+		t.Errorf("expected primary to be cooling down, got height %d", height)
+	}
+
+	// after the cooldown the preferred provider is used again
+	now = now.Add(providerCooldown + time.Second)
+	if height, _ = client.GetLatestHeight(); height != 100 {
+		t.Errorf("expected primary to be used after cooldown, got height %d", height)
+	}
+
+	// every provider failing returns an error
+	primary.Err = errors.New("timeout")
+	secondary.Err = errors.New("timeout")
+	if _, err = client.GetNodes(); err == nil {
+		t.Error("expected error when all providers fail")
+	}
+}
+
+func TestFailoverClientRequestErrors(t *testing.T) {
+	primary, secondary := NewFakeClient(), NewFakeClient()
+	secondary.TxDetails["HASH"] = &openapi.TxDetailsResponse{}
+
+	client := newFailoverClient([]ProviderClient{
+		{Name: "primary", ThornodeDataFetcher: primary},
+		{Name: "secondary", ThornodeDataFetcher: secondary},
+	})
+
+	// client errors are returned as is and don't mark the provider unhealthy
+	primary.Err = &StatusError{StatusCode: http.StatusNotFound}
+	if _, err := client.GetTxDetails("HASH"); err == nil {
+		t.Fatal("expected not found error to be returned without failover")
+	}
+	if client.providers[0].failures != 0 {
+		t.Errorf("expected primary to stay healthy, got %d failures", client.providers[0].failures)
+	}
+
+	// server errors fail over
+	primary.Err = &StatusError{StatusCode: http.StatusBadGateway}
+	if _, err := client.GetTxDetails("HASH"); err != nil {
+		t.Fatalf("expected failover on server error, got %v", err)
+	}
+	if client.providers[0].failures != 1 {
+		t.Errorf("expected primary to be marked unhealthy, got %d failures", client.providers[0].failures)
+	}
+}
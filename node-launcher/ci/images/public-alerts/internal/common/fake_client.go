@@ -0,0 +1,283 @@
+package common
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+
+	openapi "gitlab.com/thorchain/thornode/openapi/gen"
+)
+
+// FakeClient is an in-memory implementation of the THORNode, Nine Realms and
+// Midgard fetchers. Monitors can be constructed with it to exercise Check end
+// to end without network access.
+type FakeClient struct {
+	Height        int
+	Nodes         []openapi.Node
+	Invariants    map[string]*openapi.InvariantResponse
+	OutboundQueue []openapi.TxOutItem
+	Scheduled     []openapi.TxOutItem
+	Queue         openapi.QueueResponse
+	TxDetails     map[string]*openapi.TxDetailsResponse
+	Vaults        []openapi.Vault
+	Pools         []openapi.Pool
+	Savers        map[string][]openapi.Saver
+	Mimir         map[string]int64
+	AdminMimir    map[string]int64
+	MimirVotes    []openapi.MimirVote
+	Inbound       []openapi.InboundAddress
+	Commits       map[int]*BlockCommit
+	Version       *openapi.VersionResponse
+	Upgrades      []UpgradeProposal
+	Network       *openapi.NetworkResponse
+	RuneSupply    int64
+	RuneBalances  map[string]int64
+	Solvency      []SolvencyVault
+	Images        []Image
+	Prices        map[string]float64
+	Actions       []MidgardAction
+
+	// PinnedHeight records the height most recently passed to AtHeight.
+	PinnedHeight int
+
+	// Err, when set, is returned from every call.
+	Err error
+}
+
+var (
+	_ ThornodeDataFetcher   = (*FakeClient)(nil)
+	_ NineRealmsDataFetcher = (*FakeClient)(nil)
+	_ MidgardDataFetcher    = (*FakeClient)(nil)
+)
+
+// NewFakeClient returns an empty FakeClient.
+func NewFakeClient() *FakeClient {
+	return &FakeClient{
+		Invariants:   make(map[string]*openapi.InvariantResponse),
+		TxDetails:    make(map[string]*openapi.TxDetailsResponse),
+		Mimir:        make(map[string]int64),
+		AdminMimir:   make(map[string]int64),
+		Commits:      make(map[int]*BlockCommit),
+		Savers:       make(map[string][]openapi.Saver),
+		RuneBalances: make(map[string]int64),
+		Prices:       make(map[string]float64),
+	}
+}
+
+// AtHeight records the pinned height and returns the same fake, which serves
+// identical data at every height.
+func (f *FakeClient) AtHeight(height int) ThornodeDataFetcher {
+	f.PinnedHeight = height
+	return f
+}
+
+func (f *FakeClient) GetLatestHeight() (int, error) {
+	if f.Err != nil {
+		return 0, f.Err
+	}
+	return f.Height, nil
+}
+
+func (f *FakeClient) GetNodes() ([]openapi.Node, error) {
+	if f.Err != nil {
+		return nil, f.Err
+	}
+	return f.Nodes, nil
+}
+
+func (f *FakeClient) GetInvariants() ([]string, error) {
+	if f.Err != nil {
+		return nil, f.Err
+	}
+	invariants := make([]string, 0, len(f.Invariants))
+	for name := range f.Invariants {
+		invariants = append(invariants, name)
+	}
+	sort.Strings(invariants)
+	return invariants, nil
+}
+
+func (f *FakeClient) GetInvariant(invariant string) (*openapi.InvariantResponse, error) {
+	if f.Err != nil {
+		return nil, f.Err
+	}
+	response, ok := f.Invariants[invariant]
+	if !ok {
+		return nil, fmt.Errorf("invariant %s not found", invariant)
+	}
+	return response, nil
+}
+
+func (f *FakeClient) GetOutboundQueue() ([]openapi.TxOutItem, error) {
+	if f.Err != nil {
+		return nil, f.Err
+	}
+	return f.OutboundQueue, nil
+}
+
+func (f *FakeClient) GetScheduledQueue() ([]openapi.TxOutItem, error) {
+	if f.Err != nil {
+		return nil, f.Err
+	}
+	return f.Scheduled, nil
+}
+
+func (f *FakeClient) GetQueue() (*openapi.QueueResponse, error) {
+	if f.Err != nil {
+		return nil, f.Err
+	}
+	return &f.Queue, nil
+}
+
+func (f *FakeClient) GetTxDetails(hash string) (*openapi.TxDetailsResponse, error) {
+	if f.Err != nil {
+		return nil, f.Err
+	}
+	details, ok := f.TxDetails[hash]
+	if !ok {
+		return nil, fmt.Errorf("tx %s not found", hash)
+	}
+	return details, nil
+}
+
+func (f *FakeClient) GetVaults() ([]openapi.Vault, error) {
+	if f.Err != nil {
+		return nil, f.Err
+	}
+	return f.Vaults, nil
+}
+
+func (f *FakeClient) GetPools() ([]openapi.Pool, error) {
+	if f.Err != nil {
+		return nil, f.Err
+	}
+	return f.Pools, nil
+}
+
+// GetSavers returns the savers of the pool of asset, none when not set.
+func (f *FakeClient) GetSavers(asset string) ([]openapi.Saver, error) {
+	if f.Err != nil {
+		return nil, f.Err
+	}
+	return f.Savers[asset], nil
+}
+
+func (f *FakeClient) GetMimir() (map[string]int64, error) {
+	if f.Err != nil {
+		return nil, f.Err
+	}
+	return f.Mimir, nil
+}
+
+func (f *FakeClient) GetAdminMimir() (map[string]int64, error) {
+	if f.Err != nil {
+		return nil, f.Err
+	}
+	return f.AdminMimir, nil
+}
+
+func (f *FakeClient) GetMimirVotes() ([]openapi.MimirVote, error) {
+	if f.Err != nil {
+		return nil, f.Err
+	}
+	return f.MimirVotes, nil
+}
+
+func (f *FakeClient) GetInboundAddresses() ([]openapi.InboundAddress, error) {
+	if f.Err != nil {
+		return nil, f.Err
+	}
+	return f.Inbound, nil
+}
+
+func (f *FakeClient) GetSolvency() ([]SolvencyVault, error) {
+	if f.Err != nil {
+		return nil, f.Err
+	}
+	return f.Solvency, nil
+}
+
+func (f *FakeClient) GetImages() ([]Image, error) {
+	if f.Err != nil {
+		return nil, f.Err
+	}
+	return f.Images, nil
+}
+
+func (f *FakeClient) GetAssetPricesUSD() (map[string]float64, error) {
+	if f.Err != nil {
+		return nil, f.Err
+	}
+	return f.Prices, nil
+}
+
+// GetCommit returns the commit at height, or at Height when height is zero.
+func (f *FakeClient) GetCommit(height int) (*BlockCommit, error) {
+	if f.Err != nil {
+		return nil, f.Err
+	}
+	if height == 0 {
+		height = f.Height
+	}
+	commit, ok := f.Commits[height]
+	if !ok {
+		return nil, fmt.Errorf("commit %d not found", height)
+	}
+	return commit, nil
+}
+
+func (f *FakeClient) GetVersion() (*openapi.VersionResponse, error) {
+	if f.Err != nil {
+		return nil, f.Err
+	}
+	if f.Version == nil {
+		return nil, fmt.Errorf("version not set")
+	}
+	return f.Version, nil
+}
+
+func (f *FakeClient) GetUpgradeProposals() ([]UpgradeProposal, error) {
+	if f.Err != nil {
+		return nil, f.Err
+	}
+	return f.Upgrades, nil
+}
+
+func (f *FakeClient) GetNetwork() (*openapi.NetworkResponse, error) {
+	if f.Err != nil {
+		return nil, f.Err
+	}
+	if f.Network == nil {
+		return nil, fmt.Errorf("network not set")
+	}
+	return f.Network, nil
+}
+
+func (f *FakeClient) GetRuneSupply() (int64, error) {
+	if f.Err != nil {
+		return 0, f.Err
+	}
+	return f.RuneSupply, nil
+}
+
+// GetRuneBalance returns the balance of address, zero when not set.
+func (f *FakeClient) GetRuneBalance(address string) (int64, error) {
+	if f.Err != nil {
+		return 0, f.Err
+	}
+	return f.RuneBalances[address], nil
+}
+
+// GetActions returns the actions at or above fromHeight.
+func (f *FakeClient) GetActions(fromHeight int64) ([]MidgardAction, error) {
+	if f.Err != nil {
+		return nil, f.Err
+	}
+	var actions []MidgardAction
+	for _, action := range f.Actions {
+		if height, _ := strconv.ParseInt(action.Height, 10, 64); height >= fromHeight {
+			actions = append(actions, action)
+		}
+	}
+	return actions, nil
+}
@@ -0,0 +1,56 @@
+package common
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// defaultHTTPTimeout bounds every request made by the API clients.
+const defaultHTTPTimeout = 10 * time.Second
+
+// newHTTPClient returns an http.Client with the default timeout applied.
+func newHTTPClient() *http.Client {
+	return &http.Client{Timeout: defaultHTTPTimeout}
+}
+
+// StatusError is returned by getJSON when the server responds with a non-200 status.
+type StatusError struct {
+	StatusCode int
+	URL        string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("unexpected status code %d from %s", e.StatusCode, e.URL)
+}
+
+// getJSON performs a GET request against url and decodes the JSON response into target.
+// Non-200 responses are returned as errors rather than decoded.
+func getJSON(client *http.Client, url string, target interface{}) error {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return fmt.Errorf("error creating request: %w", err)
+	}
+	return doJSON(client, req, target)
+}
+
+// doJSON performs req and decodes the JSON response into target, like getJSON, for
+// requests that need headers.
+func doJSON(client *http.Client, req *http.Request, target interface{}) error {
+	url := req.URL.String()
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("error making request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return &StatusError{StatusCode: resp.StatusCode, URL: url}
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(target); err != nil {
+		return fmt.Errorf("error decoding JSON: %w", err)
+	}
+	return nil
+}
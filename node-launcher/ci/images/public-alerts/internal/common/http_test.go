@@ -0,0 +1,47 @@
+package common
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGetJSON(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/ok":
+			_, _ = w.Write([]byte(`{"invariants":["asgard","bond"]}`))
+		case "/malformed":
+			_, _ = w.Write([]byte(`{"invariants":`))
+		default:
+			http.Error(w, `{"invariants":["stale"]}`, http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name    string
+		path    string
+		want    int
+		wantErr bool
+	}{
+		{"success", "/ok", 2, false},
+		{"non-200 status", "/unavailable", 0, true},
+		{"malformed body", "/malformed", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var target struct {
+				Invariants []string `json:"invariants"`
+			}
+			err := getJSON(newHTTPClient(), server.URL+tt.path, &target)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("getJSON() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(target.Invariants) != tt.want {
+				t.Errorf("expected %d invariants, got %d", tt.want, len(target.Invariants))
+			}
+		})
+	}
+}
@@ -0,0 +1,120 @@
+package common
+
+import (
+	"fmt"
+	"net/http"
+	"public-alerts/internal/config"
+	"strconv"
+
+	"github.com/rs/zerolog/log"
+)
+
+// MidgardDataFetcher defines the interface for fetching data from the Midgard API.
+type MidgardDataFetcher interface {
+	GetAssetPricesUSD() (map[string]float64, error)
+	GetActions(fromHeight int64) ([]MidgardAction, error)
+}
+
+// MidgardAction is a swap, liquidity or other user action indexed by Midgard.
+type MidgardAction struct {
+	Date   string               `json:"date"`
+	Height string               `json:"height"`
+	Type   string               `json:"type"`
+	Status string               `json:"status"`
+	Pools  []string             `json:"pools"`
+	In     []MidgardTransaction `json:"in"`
+	Out    []MidgardTransaction `json:"out"`
+}
+
+// MidgardTransaction is one side of a Midgard action.
+type MidgardTransaction struct {
+	Address string        `json:"address"`
+	TxID    string        `json:"txID"`
+	Coins   []MidgardCoin `json:"coins"`
+}
+
+type MidgardCoin struct {
+	Asset  string `json:"asset"`
+	Amount string `json:"amount"`
+}
+
+// midgardActionTypes are the action types fetched by GetActions.
+const midgardActionTypes = "swap,addLiquidity,withdraw"
+
+// midgardMaxActionPages bounds the pages fetched by one GetActions call.
+const midgardMaxActionPages = 20
+
+// midgardClient implements the MidgardDataFetcher interface over HTTP.
+type midgardClient struct {
+	httpClient *http.Client
+	baseURL    string
+	prices     *TTLCache[string, map[string]float64]
+}
+
+// NewMidgardClient creates a new client for interacting with the Midgard API.
+func NewMidgardClient() MidgardDataFetcher {
+	return NewMidgardClientWithURL(config.Get().Endpoints.MidgardAPI)
+}
+
+// NewMidgardClientWithURL creates a Midgard client for the given base URL.
+func NewMidgardClientWithURL(baseURL string) MidgardDataFetcher {
+	return &midgardClient{
+		httpClient: newHTTPClient(),
+		baseURL:    baseURL,
+		prices:     NewTTLCache[string, map[string]float64](config.Get().Pricing.CacheTTL),
+	}
+}
+
+// GetAssetPricesUSD fetches asset prices from the Midgard API and caches them.
+func (c *midgardClient) GetAssetPricesUSD() (map[string]float64, error) {
+	return c.prices.Get("pools", c.fetchAssetPricesUSD)
+}
+
+func (c *midgardClient) fetchAssetPricesUSD() (map[string]float64, error) {
+	var pools []struct {
+		Asset         string `json:"asset"`
+		AssetPriceUSD string `json:"assetPriceUSD"`
+	}
+	if err := getJSON(c.httpClient, fmt.Sprintf("%s/v2/pools", c.baseURL), &pools); err != nil {
+		return nil, fmt.Errorf("failed to get pools: %w", err)
+	}
+
+	prices := make(map[string]float64)
+	for _, pool := range pools {
+		price, err := strconv.ParseFloat(pool.AssetPriceUSD, 64)
+		if err != nil {
+			continue
+		}
+		prices[pool.Asset] = price
+	}
+	return prices, nil
+}
+
+// GetActions returns the swap and liquidity actions at or above fromHeight, newest
+// first, paging through at most midgardMaxActionPages pages.
+func (c *midgardClient) GetActions(fromHeight int64) ([]MidgardAction, error) {
+	var actions []MidgardAction
+	pageToken := ""
+	for page := 0; page < midgardMaxActionPages; page++ {
+		url := fmt.Sprintf("%s/v2/actions?limit=50&type=%s&fromHeight=%d", c.baseURL, midgardActionTypes, fromHeight)
+		if pageToken != "" {
+			url += "&nextPageToken=" + pageToken
+		}
+		var response struct {
+			Actions []MidgardAction `json:"actions"`
+			Meta    struct {
+				NextPageToken string `json:"nextPageToken"`
+			} `json:"meta"`
+		}
+		if err := getJSON(c.httpClient, url, &response); err != nil {
+			return nil, fmt.Errorf("failed to get actions: %w", err)
+		}
+		actions = append(actions, response.Actions...)
+		if len(response.Actions) == 0 || response.Meta.NextPageToken == "" {
+			return actions, nil
+		}
+		pageToken = response.Meta.NextPageToken
+	}
+	log.Warn().Int64("from_height", fromHeight).Msg("too many midgard actions, older actions skipped")
+	return actions, nil
+}
@@ -0,0 +1,36 @@
+package common
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestMidgardClientGetActions(t *testing.T) {
+	var queries []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.RawQuery)
+		if r.URL.Query().Get("nextPageToken") == "" {
+			fmt.Fprint(w, `{"actions": [{"height": "102", "type": "swap"}, {"height": "101", "type": "swap"}], "meta": {"nextPageToken": "abc"}}`)
+			return
+		}
+		fmt.Fprint(w, `{"actions": [{"height": "100", "type": "withdraw"}], "meta": {}}`)
+	}))
+	defer server.Close()
+
+	actions, err := NewMidgardClientWithURL(server.URL).GetActions(100)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(actions) != 3 || actions[2].Type != "withdraw" {
+		t.Errorf("expected actions from both pages, got %+v", actions)
+	}
+	expected := []string{
+		"limit=50&type=swap,addLiquidity,withdraw&fromHeight=100",
+		"limit=50&type=swap,addLiquidity,withdraw&fromHeight=100&nextPageToken=abc",
+	}
+	if len(queries) != 2 || queries[0] != expected[0] || queries[1] != expected[1] {
+		t.Errorf("expected queries %q, got %q", expected, queries)
+	}
+}
@@ -0,0 +1,78 @@
+package common
+
+import (
+	"fmt"
+	"net/http"
+	"public-alerts/internal/config"
+
+	openapi "gitlab.com/thorchain/thornode/openapi/gen"
+)
+
+// SolvencyCoin is a vault coin as reported by the Nine Realms solvency endpoint,
+// pairing the THORChain accounted amount with the amount actually held on chain.
+type SolvencyCoin struct {
+	ChainAmount string `json:"chain_amount"`
+	Amount      string `json:"amount"`
+	Asset       string `json:"asset"`
+}
+
+// SolvencyVault is a vault as reported by the Nine Realms solvency endpoint.
+type SolvencyVault struct {
+	Status    string                 `json:"status"`
+	Addresses []openapi.VaultAddress `json:"addresses"`
+	Coins     []SolvencyCoin         `json:"coins"`
+	PubKey    string                 `json:"pub_key"`
+	Type      string                 `json:"type"`
+}
+
+// Image is a docker image published by the Nine Realms security endpoint.
+type Image struct {
+	Repo         string `json:"repo"`
+	Tag          string `json:"tag"`
+	Hash         string `json:"hash"`
+	PreviousHash string `json:"previous_hash"`
+}
+
+// NineRealmsDataFetcher defines the interface for fetching data from the Nine Realms API.
+type NineRealmsDataFetcher interface {
+	GetSolvency() ([]SolvencyVault, error)
+	GetImages() ([]Image, error)
+}
+
+// nineRealmsClient implements the NineRealmsDataFetcher interface over HTTP.
+type nineRealmsClient struct {
+	httpClient *http.Client
+	baseURL    string
+}
+
+// NewNineRealmsClient creates a new client for interacting with the Nine Realms API.
+func NewNineRealmsClient() NineRealmsDataFetcher {
+	return NewNineRealmsClientWithURL(config.Get().Endpoints.NineRealmsAPI)
+}
+
+// NewNineRealmsClientWithURL creates a Nine Realms client for the given base URL.
+func NewNineRealmsClientWithURL(baseURL string) NineRealmsDataFetcher {
+	return &nineRealmsClient{
+		httpClient: newHTTPClient(),
+		baseURL:    baseURL,
+	}
+}
+
+// GetSolvency returns the asgard vaults with their accounted and on-chain balances.
+func (c *nineRealmsClient) GetSolvency() ([]SolvencyVault, error) {
+	var vaults []SolvencyVault
+	if err := getJSON(c.httpClient, fmt.Sprintf("%s/thorchain/solvency/asgard", c.baseURL), &vaults); err != nil {
+		return nil, err
+	}
+	return vaults, nil
+}
+
+// GetImages returns the published docker images and their hashes.
+// TODO - switch to new non-9R API endpoint, when available
+func (c *nineRealmsClient) GetImages() ([]Image, error) {
+	var images []Image
+	if err := getJSON(c.httpClient, fmt.Sprintf("%s/thorchain/security/images", c.baseURL), &images); err != nil {
+		return nil, fmt.Errorf("failed to fetch images: %w", err)
+	}
+	return images, nil
+}
@@ -0,0 +1,129 @@
+package common
+
+import (
+	"fmt"
+	"net/http"
+	"public-alerts/internal/config"
+	"sort"
+	"strconv"
+
+	"github.com/rs/zerolog/log"
+	openapi "gitlab.com/thorchain/thornode/openapi/gen"
+)
+
+// RuneAsset is the asset key under which the RUNE price is reported.
+const RuneAsset = "THOR.RUNE"
+
+// PriceFetcher provides USD prices keyed by asset.
+type PriceFetcher interface {
+	GetAssetPricesUSD() (map[string]float64, error)
+}
+
+// thornodePriceFetcher prices assets from THORNode pool depths, falling back to
+// another source when THORNode is unavailable or no stable pool can be priced.
+type thornodePriceFetcher struct {
+	thornode    ThornodeDataFetcher
+	fallback    PriceFetcher
+	stablePools []string
+}
+
+// NewPriceFetcher creates a PriceFetcher deriving USD prices from THORNode pools,
+// using the configured stable pools to price RUNE and fallback (e.g. Midgard)
+// when THORNode prices cannot be computed.
+func NewPriceFetcher(thornode ThornodeDataFetcher, fallback PriceFetcher) PriceFetcher {
+	return &thornodePriceFetcher{
+		thornode:    thornode,
+		fallback:    fallback,
+		stablePools: config.Get().Pricing.StablePools,
+	}
+}
+
+// GetAssetPricesUSD returns the USD price of every available pool asset and RUNE.
+func (c *thornodePriceFetcher) GetAssetPricesUSD() (map[string]float64, error) {
+	pools, err := c.thornode.GetPools()
+	if err == nil {
+		var prices map[string]float64
+		if prices, err = PoolPricesUSD(pools, c.stablePools); err == nil {
+			return prices, nil
+		}
+	}
+	log.Warn().Err(err).Msg("failed to price assets from thornode pools, falling back")
+	return c.fallback.GetAssetPricesUSD()
+}
+
+// PoolRunePrices returns the RUNE price of each available pool asset from its depths.
+func PoolRunePrices(pools []openapi.Pool) map[string]float64 {
+	runePerAsset := make(map[string]float64)
+	for _, pool := range pools {
+		if pool.Status != "Available" {
+			continue
+		}
+		assetDepth, err := strconv.ParseFloat(pool.BalanceAsset, 64)
+		if err != nil || assetDepth <= 0 {
+			continue
+		}
+		runeDepth, err := strconv.ParseFloat(pool.BalanceRune, 64)
+		if err != nil || runeDepth <= 0 {
+			continue
+		}
+		runePerAsset[pool.Asset] = runeDepth / assetDepth
+	}
+	return runePerAsset
+}
+
+// PoolPricesUSD computes USD prices from pool depths. The RUNE price is the median
+// of the RUNE prices implied by the available stable pools, and each asset is
+// priced from its RUNE price in its pool.
+func PoolPricesUSD(pools []openapi.Pool, stablePools []string) (map[string]float64, error) {
+	runePerAsset := PoolRunePrices(pools)
+
+	// stable assets are worth $1, so RUNE is worth the inverse of their RUNE price
+	var runePrices []float64
+	for _, asset := range stablePools {
+		if price, ok := runePerAsset[asset]; ok {
+			runePrices = append(runePrices, 1/price)
+		}
+	}
+	if len(runePrices) == 0 {
+		return nil, fmt.Errorf("no available stable pools to price RUNE")
+	}
+	sort.Float64s(runePrices)
+	runeUSD := runePrices[len(runePrices)/2]
+	if len(runePrices)%2 == 0 {
+		runeUSD = (runePrices[len(runePrices)/2-1] + runeUSD) / 2
+	}
+
+	prices := map[string]float64{RuneAsset: runeUSD}
+	for asset, price := range runePerAsset {
+		prices[asset] = price * runeUSD
+	}
+	return prices, nil
+}
+
+// referencePriceFetcher reads USD prices from an external JSON feed of asset to price.
+type referencePriceFetcher struct {
+	httpClient *http.Client
+	url        string
+	prices     *TTLCache[string, map[string]float64]
+}
+
+// NewReferencePriceFetcher creates a PriceFetcher for a reference feed at url
+// serving a JSON object of asset to USD price, e.g. {"BTC.BTC": 60000.5}.
+func NewReferencePriceFetcher(url string) PriceFetcher {
+	return &referencePriceFetcher{
+		httpClient: newHTTPClient(),
+		url:        url,
+		prices:     NewTTLCache[string, map[string]float64](config.Get().Pricing.CacheTTL),
+	}
+}
+
+// GetAssetPricesUSD fetches the reference prices and caches them.
+func (c *referencePriceFetcher) GetAssetPricesUSD() (map[string]float64, error) {
+	return c.prices.Get("prices", func() (map[string]float64, error) {
+		var prices map[string]float64
+		if err := getJSON(c.httpClient, c.url, &prices); err != nil {
+			return nil, fmt.Errorf("failed to get reference prices: %w", err)
+		}
+		return prices, nil
+	})
+}
@@ -0,0 +1,91 @@
+package common
+
+import (
+	"errors"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	openapi "gitlab.com/thorchain/thornode/openapi/gen"
+)
+
+func testPool(asset, status, assetDepth, runeDepth string) openapi.Pool {
+	return openapi.Pool{Asset: asset, Status: status, BalanceAsset: assetDepth, BalanceRune: runeDepth}
+}
+
+func TestPoolPricesUSD(t *testing.T) {
+	pools := []openapi.Pool{
+		testPool("ETH.USDC", "Available", "500000000", "100000000"),  // RUNE = $5
+		testPool("ETH.USDT", "Available", "600000000", "100000000"),  // RUNE = $6
+		testPool("BSC.USDT", "Available", "9900000000", "100000000"), // depegged outlier
+		testPool("BTC.BTC", "Available", "100000000", "2000000000"),
+		testPool("DOGE.DOGE", "Staged", "100000000", "100000000"),
+		testPool("LTC.LTC", "Available", "0", "0"),
+	}
+
+	prices, err := PoolPricesUSD(pools, []string{"ETH.USDC", "ETH.USDT", "BSC.USDT", "AVAX.USDC"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if prices[RuneAsset] != 6 {
+		t.Errorf("expected median RUNE price 6, got %v", prices[RuneAsset])
+	}
+	if math.Abs(prices["BTC.BTC"]-120) > 1e-9 {
+		t.Errorf("expected BTC price 120, got %v", prices["BTC.BTC"])
+	}
+	for _, asset := range []string{"DOGE.DOGE", "LTC.LTC"} {
+		if _, ok := prices[asset]; ok {
+			t.Errorf("expected %s to be unpriced", asset)
+		}
+	}
+
+	if _, err := PoolPricesUSD(pools, []string{"AVAX.USDC"}); err == nil {
+		t.Error("expected error without an available stable pool")
+	}
+}
+
+func TestPriceFetcherFallback(t *testing.T) {
+	thornode := NewFakeClient()
+	thornode.Pools = []openapi.Pool{
+		testPool("ETH.USDC", "Available", "200000000", "100000000"),
+		testPool("BTC.BTC", "Available", "100000000", "1000000000"),
+	}
+	midgard := NewFakeClient()
+	midgard.Prices["BTC.BTC"] = 30
+
+	fetcher := &thornodePriceFetcher{thornode: thornode, fallback: midgard, stablePools: []string{"ETH.USDC"}}
+
+	prices, err := fetcher.GetAssetPricesUSD()
+	if err != nil || prices["BTC.BTC"] != 20 {
+		t.Errorf("expected thornode BTC price 20, got %v (%v)", prices["BTC.BTC"], err)
+	}
+
+	// missing stable pool
+	fetcher.stablePools = []string{"ETH.USDT"}
+	if prices, _ = fetcher.GetAssetPricesUSD(); prices["BTC.BTC"] != 30 {
+		t.Errorf("expected midgard BTC price 30, got %v", prices["BTC.BTC"])
+	}
+
+	// thornode unavailable
+	fetcher.stablePools = []string{"ETH.USDC"}
+	thornode.Err = errors.New("unavailable")
+	if prices, _ = fetcher.GetAssetPricesUSD(); prices["BTC.BTC"] != 30 {
+		t.Errorf("expected midgard BTC price 30, got %v", prices["BTC.BTC"])
+	}
+}
+
+func TestReferencePriceFetcher(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"THOR.RUNE": 5.5, "BTC.BTC": 60000}`))
+	}))
+	defer server.Close()
+
+	prices, err := NewReferencePriceFetcher(server.URL).GetAssetPricesUSD()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if prices[RuneAsset] != 5.5 || prices["BTC.BTC"] != 60000 {
+		t.Errorf("unexpected reference prices %v", prices)
+	}
+}
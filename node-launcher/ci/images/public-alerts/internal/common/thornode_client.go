@@ -2,10 +2,10 @@ package common
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"net/http"
 	"public-alerts/internal/config"
+	"strconv"
 	"time"
 
 	tmhttp "github.com/tendermint/tendermint/rpc/client/http"
@@ -18,6 +18,57 @@ type ThornodeDataFetcher interface {
 	GetNodes() ([]openapi.Node, error)
 	GetInvariants() ([]string, error)
 	GetInvariant(invariant string) (*openapi.InvariantResponse, error)
+	GetOutboundQueue() ([]openapi.TxOutItem, error)
+	GetScheduledQueue() ([]openapi.TxOutItem, error)
+	GetQueue() (*openapi.QueueResponse, error)
+	GetTxDetails(hash string) (*openapi.TxDetailsResponse, error)
+	GetVaults() ([]openapi.Vault, error)
+	GetPools() ([]openapi.Pool, error)
+	GetSavers(asset string) ([]openapi.Saver, error)
+	GetMimir() (map[string]int64, error)
+	GetAdminMimir() (map[string]int64, error)
+	GetMimirVotes() ([]openapi.MimirVote, error)
+	GetInboundAddresses() ([]openapi.InboundAddress, error)
+	GetCommit(height int) (*BlockCommit, error)
+	GetVersion() (*openapi.VersionResponse, error)
+	GetUpgradeProposals() ([]UpgradeProposal, error)
+	GetNetwork() (*openapi.NetworkResponse, error)
+	GetRuneSupply() (int64, error)
+	GetRuneBalance(address string) (int64, error)
+
+	// AtHeight returns a view of the client that issues every API query at the given
+	// height and reports it as the latest height, so a check sees a consistent block.
+	AtHeight(height int) ThornodeDataFetcher
+}
+
+// PinLatestHeight resolves the latest height once and returns the client pinned to it.
+func PinLatestHeight(client ThornodeDataFetcher) (ThornodeDataFetcher, int, error) {
+	height, err := client.GetLatestHeight()
+	if err != nil {
+		return nil, 0, err
+	}
+	return client.AtHeight(height), height, nil
+}
+
+// BlockCommit is a committed block with the validator precommits that signed it.
+type BlockCommit struct {
+	Height      int
+	Time        time.Time
+	Validators  int   // validators in the set that committed the block
+	Signers     int   // validators that precommitted the block
+	TotalPower  int64 // voting power of the validator set
+	SignedPower int64 // voting power that precommitted the block
+}
+
+// UpgradeProposal is a proposed software upgrade with its node approval. The
+// pinned openapi models predate the upgrade proposal endpoint.
+type UpgradeProposal struct {
+	Name               string `json:"name"`
+	Height             int64  `json:"height"`
+	Info               string `json:"info"`
+	Approved           bool   `json:"approved"`
+	ApprovedPercent    string `json:"approved_percent"`
+	ValidatorsToQuorum int64  `json:"validators_to_quorum"`
 }
 
 // thornodeClient implements the ThornodeDataFetcher interface using Thornode's HTTP and RPC endpoints.
@@ -25,24 +76,80 @@ type thornodeClient struct {
 	httpClient *http.Client
 	rpcClient  *tmhttp.HTTP // Tendermint RPC client
 	baseURL    string
+	height     int // queries are pinned to this height when non-zero
 }
 
-// NewThornodeClient creates a new client for interacting with Thornode.
+// NewThornodeClient creates a new client for interacting with Thornode. Requests
+// fail over between the configured providers in order of preference, and commonly
+// polled queries are cached so monitors checking at the same time share them.
 func NewThornodeClient() (ThornodeDataFetcher, error) {
-	rpcClient, err := tmhttp.New(config.Get().Endpoints.ThornodeRPC, "/websocket")
+	return NewThornodeClientWithProviders(config.Get().ThornodeProviders)
+}
+
+// NewThornodeClientWithProviders creates a client failing over between the given providers.
+func NewThornodeClientWithProviders(providers []config.ThornodeProvider) (ThornodeDataFetcher, error) {
+	clients, err := NewThornodeProviderClients(providers)
+	if err != nil {
+		return nil, err
+	}
+	return newCachingClient(newFailoverClient(clients), config.Get().Pricing.CacheTTL), nil
+}
+
+// ProviderClient is a client bound to a single named THORNode provider.
+type ProviderClient struct {
+	Name string
+	ThornodeDataFetcher
+}
+
+// NewThornodeProviderClients creates one client per THORNode provider.
+func NewThornodeProviderClients(providers []config.ThornodeProvider) ([]ProviderClient, error) {
+	var clients []ProviderClient
+	for _, provider := range providers {
+		client, err := newThornodeClient(provider.API, provider.RPC)
+		if err != nil {
+			return nil, fmt.Errorf("provider %s: %w", provider.Name, err)
+		}
+		clients = append(clients, ProviderClient{Name: provider.Name, ThornodeDataFetcher: client})
+	}
+	return clients, nil
+}
+
+// newThornodeClient creates a client for a single API and RPC endpoint.
+func newThornodeClient(apiURL, rpcURL string) (*thornodeClient, error) {
+	httpClient := newHTTPClient()
+	rpcClient, err := tmhttp.NewWithClient(rpcURL, "/websocket", httpClient)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create RPC client: %w", err)
 	}
 	return &thornodeClient{
-		httpClient: &http.Client{Timeout: 10 * time.Second},
+		httpClient: httpClient,
 		rpcClient:  rpcClient,
-		baseURL:    config.Get().Endpoints.ThornodeAPI,
+		baseURL:    apiURL,
 	}, nil
 }
 
+// url returns the API url for path, pinned to the client height if set.
+func (c *thornodeClient) url(path string) string {
+	if c.height > 0 {
+		return fmt.Sprintf("%s%s?height=%d", c.baseURL, path, c.height)
+	}
+	return c.baseURL + path
+}
+
+// AtHeight returns a copy of the client with its queries pinned to height.
+func (c *thornodeClient) AtHeight(height int) ThornodeDataFetcher {
+	pinned := *c
+	pinned.height = height
+	return &pinned
+}
+
 // GetLatestHeight returns the latest block height from the Thornode network.
 func (c *thornodeClient) GetLatestHeight() (int, error) {
-	ctx := context.Background()
+	if c.height > 0 {
+		return c.height, nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), defaultHTTPTimeout)
+	defer cancel()
 	status, err := c.rpcClient.Status(ctx)
 	if err != nil {
 		return 0, fmt.Errorf("failed to get current height: %w", err)
@@ -52,50 +159,267 @@ func (c *thornodeClient) GetLatestHeight() (int, error) {
 
 // GetNodes retrieves the list of nodes from the Thornode network.
 func (c *thornodeClient) GetNodes() ([]openapi.Node, error) {
-	resp, err := c.httpClient.Get(fmt.Sprintf("%s/thorchain/nodes", c.baseURL))
-	if err != nil {
-		return nil, fmt.Errorf("error making request: %w", err)
-	}
-	defer resp.Body.Close()
-
 	var nodes []openapi.Node
-	if err := json.NewDecoder(resp.Body).Decode(&nodes); err != nil {
-		return nil, fmt.Errorf("error decoding JSON: %w", err)
+	if err := getJSON(c.httpClient, c.url("/thorchain/nodes"), &nodes); err != nil {
+		return nil, err
 	}
 	return nodes, nil
 }
 
 // GetInvariants retrieves a list of invariants from the Thornode network.
 func (c *thornodeClient) GetInvariants() ([]string, error) {
+	var invars openapi.InvariantsResponse
+	if err := getJSON(c.httpClient, c.url("/thorchain/invariants"), &invars); err != nil {
+		return nil, err
+	}
+	return invars.Invariants, nil
+}
+
+// GetInvariant returns the status of a specific invariant from the Thornode network.
+func (c *thornodeClient) GetInvariant(invariant string) (*openapi.InvariantResponse, error) {
+	var response openapi.InvariantResponse
+	if err := getJSON(c.httpClient, c.url("/thorchain/invariant/"+invariant), &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// GetOutboundQueue returns the items currently in the outbound queue.
+func (c *thornodeClient) GetOutboundQueue() ([]openapi.TxOutItem, error) {
+	var items []openapi.TxOutItem
+	if err := getJSON(c.httpClient, c.url("/thorchain/queue/outbound"), &items); err != nil {
+		return nil, fmt.Errorf("error fetching outbound transactions: %w", err)
+	}
+	return items, nil
+}
+
+// GetScheduledQueue returns the outbounds scheduled for a later height.
+func (c *thornodeClient) GetScheduledQueue() ([]openapi.TxOutItem, error) {
+	var items []openapi.TxOutItem
+	if err := getJSON(c.httpClient, c.url("/thorchain/queue/scheduled"), &items); err != nil {
+		return nil, fmt.Errorf("error fetching scheduled outbounds: %w", err)
+	}
+	return items, nil
+}
+
+// GetQueue returns the number of queued swaps, outbounds and internal transactions.
+func (c *thornodeClient) GetQueue() (*openapi.QueueResponse, error) {
+	var queue openapi.QueueResponse
+	if err := getJSON(c.httpClient, c.url("/thorchain/queue"), &queue); err != nil {
+		return nil, fmt.Errorf("error fetching queue: %w", err)
+	}
+	return &queue, nil
+}
+
+// GetTxDetails returns the details of the transaction with the given inbound hash.
+func (c *thornodeClient) GetTxDetails(hash string) (*openapi.TxDetailsResponse, error) {
+	var details openapi.TxDetailsResponse
+	if err := getJSON(c.httpClient, c.url("/thorchain/tx/details/"+hash), &details); err != nil {
+		return nil, fmt.Errorf("error fetching transaction details: %w", err)
+	}
+	return &details, nil
+}
 
-	type InvariantsResp struct {
-		Invariants []string `json:"invariants"`
+// GetVaults returns the active and retiring asgard vaults.
+func (c *thornodeClient) GetVaults() ([]openapi.Vault, error) {
+	var vaults []openapi.Vault
+	if err := getJSON(c.httpClient, c.url("/thorchain/vaults/asgard"), &vaults); err != nil {
+		return nil, fmt.Errorf("error fetching asgard vaults: %w", err)
 	}
-	var invars InvariantsResp
-	resp, err := c.httpClient.Get(fmt.Sprintf("%s/thorchain/invariants", c.baseURL))
+	return vaults, nil
+}
+
+// GetPools returns all liquidity pools.
+func (c *thornodeClient) GetPools() ([]openapi.Pool, error) {
+	var pools []openapi.Pool
+	if err := getJSON(c.httpClient, c.url("/thorchain/pools"), &pools); err != nil {
+		return nil, fmt.Errorf("error fetching pools: %w", err)
+	}
+	return pools, nil
+}
+
+// GetSavers returns the savers of the pool of the given asset.
+func (c *thornodeClient) GetSavers(asset string) ([]openapi.Saver, error) {
+	var savers []openapi.Saver
+	if err := getJSON(c.httpClient, c.url("/thorchain/pool/"+asset+"/savers"), &savers); err != nil {
+		return nil, fmt.Errorf("error fetching savers for %s: %w", asset, err)
+	}
+	return savers, nil
+}
+
+// GetMimir returns the effective mimir values by key.
+func (c *thornodeClient) GetMimir() (map[string]int64, error) {
+	var mimir map[string]int64
+	if err := getJSON(c.httpClient, c.url("/thorchain/mimir"), &mimir); err != nil {
+		return nil, fmt.Errorf("error fetching mimir: %w", err)
+	}
+	return mimir, nil
+}
+
+// GetInboundAddresses returns the inbound address and status of each chain.
+func (c *thornodeClient) GetInboundAddresses() ([]openapi.InboundAddress, error) {
+	var addresses []openapi.InboundAddress
+	if err := getJSON(c.httpClient, c.url("/thorchain/inbound_addresses"), &addresses); err != nil {
+		return nil, fmt.Errorf("error fetching inbound addresses: %w", err)
+	}
+	return addresses, nil
+}
+
+// GetAdminMimir returns the mimir values set by the admin.
+func (c *thornodeClient) GetAdminMimir() (map[string]int64, error) {
+	var mimir map[string]int64
+	if err := getJSON(c.httpClient, c.url("/thorchain/mimir/admin"), &mimir); err != nil {
+		return nil, fmt.Errorf("error fetching admin mimir: %w", err)
+	}
+	return mimir, nil
+}
+
+// GetMimirVotes returns the mimir votes of every node.
+func (c *thornodeClient) GetMimirVotes() ([]openapi.MimirVote, error) {
+	var votes openapi.MimirNodesResponse
+	if err := getJSON(c.httpClient, c.url("/thorchain/mimir/nodes_all"), &votes); err != nil {
+		return nil, fmt.Errorf("error fetching node mimir votes: %w", err)
+	}
+	return votes.Mimirs, nil
+}
+
+// GetVersion returns the current and next network versions.
+func (c *thornodeClient) GetVersion() (*openapi.VersionResponse, error) {
+	var version openapi.VersionResponse
+	if err := getJSON(c.httpClient, c.url("/thorchain/version"), &version); err != nil {
+		return nil, fmt.Errorf("error fetching version: %w", err)
+	}
+	return &version, nil
+}
+
+// GetNetwork returns the network wide reserve, bond and fee figures.
+func (c *thornodeClient) GetNetwork() (*openapi.NetworkResponse, error) {
+	var network openapi.NetworkResponse
+	if err := getJSON(c.httpClient, c.url("/thorchain/network"), &network); err != nil {
+		return nil, fmt.Errorf("error fetching network: %w", err)
+	}
+	return &network, nil
+}
+
+// GetRuneSupply returns the total RUNE supply of the bank module. The cosmos gateway
+// reads the height from a header rather than the query, so it is pinned there.
+func (c *thornodeClient) GetRuneSupply() (int64, error) {
+	req, err := http.NewRequest(http.MethodGet, c.baseURL+"/cosmos/bank/v1beta1/supply/rune", nil)
 	if err != nil {
-		return nil, fmt.Errorf("error making request: %w", err)
+		return 0, fmt.Errorf("error fetching RUNE supply: %w", err)
 	}
-	defer resp.Body.Close()
+	if c.height > 0 {
+		req.Header.Set("x-cosmos-block-height", strconv.Itoa(c.height))
+	}
+	var supply struct {
+		Amount struct {
+			Denom  string `json:"denom"`
+			Amount string `json:"amount"`
+		} `json:"amount"`
+	}
+	if err := doJSON(c.httpClient, req, &supply); err != nil {
+		return 0, fmt.Errorf("error fetching RUNE supply: %w", err)
+	}
+	amount, err := strconv.ParseInt(supply.Amount.Amount, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("error parsing RUNE supply %q: %w", supply.Amount.Amount, err)
+	}
+	return amount, nil
+}
 
-	if err := json.NewDecoder(resp.Body).Decode(&invars); err != nil {
-		return nil, fmt.Errorf("error decoding JSON: %w", err)
+// GetRuneBalance returns the RUNE balance of address, pinned like GetRuneSupply.
+func (c *thornodeClient) GetRuneBalance(address string) (int64, error) {
+	req, err := http.NewRequest(http.MethodGet, c.baseURL+"/cosmos/bank/v1beta1/balances/"+address, nil)
+	if err != nil {
+		return 0, fmt.Errorf("error fetching balance of %s: %w", address, err)
 	}
+	if c.height > 0 {
+		req.Header.Set("x-cosmos-block-height", strconv.Itoa(c.height))
+	}
+	var balances struct {
+		Balances []struct {
+			Denom  string `json:"denom"`
+			Amount string `json:"amount"`
+		} `json:"balances"`
+	}
+	if err := doJSON(c.httpClient, req, &balances); err != nil {
+		return 0, fmt.Errorf("error fetching balance of %s: %w", address, err)
+	}
+	for _, balance := range balances.Balances {
+		if balance.Denom != "rune" {
+			continue
+		}
+		amount, err := strconv.ParseInt(balance.Amount, 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("error parsing balance of %s %q: %w", address, balance.Amount, err)
+		}
+		return amount, nil
+	}
+	return 0, nil // accounts without RUNE omit the denom
+}
 
-	return invars.Invariants, nil
+// GetUpgradeProposals returns the proposed software upgrades.
+func (c *thornodeClient) GetUpgradeProposals() ([]UpgradeProposal, error) {
+	var proposals []UpgradeProposal
+	if err := getJSON(c.httpClient, c.url("/thorchain/upgrade_proposals"), &proposals); err != nil {
+		return nil, fmt.Errorf("error fetching upgrade proposals: %w", err)
+	}
+	return proposals, nil
 }
 
-// GetInvariant returns the status of a specific invariant from the Thornode network.
-func (c *thornodeClient) GetInvariant(invariant string) (*openapi.InvariantResponse, error) {
-	resp, err := c.httpClient.Get(fmt.Sprintf("%s/thorchain/invariant/%s", c.baseURL, invariant))
+// validatorsPerPage is the largest page size accepted by the Tendermint /validators endpoint.
+const validatorsPerPage = 100
+
+// GetCommit returns the commit of the block at height from the Tendermint RPC, with
+// the precommitted voting power tallied against the validator set at that height.
+// A zero height returns the commit of the latest block, or of the pinned height.
+func (c *thornodeClient) GetCommit(height int) (*BlockCommit, error) {
+	if height == 0 {
+		height = c.height
+	}
+	var h *int64
+	if height > 0 {
+		h64 := int64(height)
+		h = &h64
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), defaultHTTPTimeout)
+	defer cancel()
+
+	commit, err := c.rpcClient.Commit(ctx, h)
 	if err != nil {
-		return nil, fmt.Errorf("error making request: %w", err)
+		return nil, fmt.Errorf("error fetching commit: %w", err)
+	}
+	// the validator set must be the one that signed this block, not the latest
+	setHeight := commit.Height
+	power := make(map[string]int64)
+	for page := 1; ; page++ {
+		perPage := validatorsPerPage
+		validators, err := c.rpcClient.Validators(ctx, &setHeight, &page, &perPage)
+		if err != nil {
+			return nil, fmt.Errorf("error fetching validators: %w", err)
+		}
+		for _, v := range validators.Validators {
+			power[v.Address.String()] = v.VotingPower
+		}
+		if len(validators.Validators) == 0 || len(power) >= validators.Total {
+			break
+		}
 	}
-	defer resp.Body.Close()
 
-	var response openapi.InvariantResponse
-	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
-		return nil, fmt.Errorf("error decoding JSON: %w", err)
+	result := &BlockCommit{
+		Height:     int(commit.Height),
+		Time:       commit.Time,
+		Validators: len(power),
 	}
-	return &response, nil
+	for _, p := range power {
+		result.TotalPower += p
+	}
+	for _, sig := range commit.Commit.Signatures {
+		if !sig.ForBlock() {
+			continue
+		}
+		result.Signers++
+		result.SignedPower += power[sig.ValidatorAddress.String()]
+	}
+	return result, nil
 }
@@ -0,0 +1,187 @@
+package common
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/tendermint/tendermint/crypto"
+	"github.com/tendermint/tendermint/crypto/ed25519"
+	ctypes "github.com/tendermint/tendermint/rpc/core/types"
+	rpctypes "github.com/tendermint/tendermint/rpc/jsonrpc/types"
+	"github.com/tendermint/tendermint/types"
+)
+
+func TestThornodeClientAtHeight(t *testing.T) {
+	var queries []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.RawQuery)
+		_, _ = w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+
+	client, err := newThornodeClient(server.URL, server.URL)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	pinned := client.AtHeight(12345)
+	height, err := pinned.GetLatestHeight()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if height != 12345 {
+		t.Errorf("expected pinned height 12345, got %d", height)
+	}
+
+	if _, err := pinned.GetNodes(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := client.GetNodes(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(queries) != 2 || queries[0] != "height=12345" || queries[1] != "" {
+		t.Errorf("expected only the pinned query to carry the height, got %q", queries)
+	}
+}
+
+func TestFailoverClientAtHeight(t *testing.T) {
+	primary := NewFakeClient()
+	primary.Height = 500
+
+	client := newFailoverClient([]ProviderClient{{Name: "primary", ThornodeDataFetcher: primary}})
+	pinned, height, err := PinLatestHeight(client)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if height != 500 {
+		t.Errorf("expected height 500, got %d", height)
+	}
+
+	if _, err := pinned.GetVaults(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if primary.PinnedHeight != 500 {
+		t.Errorf("expected provider query to be pinned to 500, got %d", primary.PinnedHeight)
+	}
+}
+
+func TestThornodeClientGetCommit(t *testing.T) {
+	keys := []crypto.PubKey{ed25519.GenPrivKey().PubKey(), ed25519.GenPrivKey().PubKey(), ed25519.GenPrivKey().PubKey()}
+	validators := make([]*types.Validator, len(keys))
+	for i, key := range keys {
+		validators[i] = types.NewValidator(key, int64(10*(i+1)))
+	}
+	blockTime := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	commit := &ctypes.ResultCommit{SignedHeader: types.SignedHeader{
+		Header: &types.Header{Height: 100, Time: blockTime},
+		Commit: &types.Commit{Height: 100, Signatures: []types.CommitSig{
+			{BlockIDFlag: types.BlockIDFlagCommit, ValidatorAddress: validators[0].Address},
+			{BlockIDFlag: types.BlockIDFlagNil, ValidatorAddress: validators[1].Address},
+			{BlockIDFlag: types.BlockIDFlagCommit, ValidatorAddress: validators[2].Address},
+		}},
+	}}
+
+	var validatorQueries []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req rpctypes.RPCRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		var result interface{}
+		switch req.Method {
+		case "commit":
+			result = commit
+		case "validators":
+			validatorQueries = append(validatorQueries, string(req.Params))
+			// serve the set over two pages
+			var params struct{ Page string }
+			_ = json.Unmarshal(req.Params, &params)
+			page := validators[:2]
+			if params.Page == "2" {
+				page = validators[2:]
+			}
+			result = &ctypes.ResultValidators{BlockHeight: 100, Validators: page, Count: len(page), Total: len(validators)}
+		}
+		_ = json.NewEncoder(w).Encode(rpctypes.NewRPCSuccessResponse(req.ID, result))
+	}))
+	defer server.Close()
+
+	client, err := newThornodeClient(server.URL, server.URL)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got, err := client.GetCommit(0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := &BlockCommit{Height: 100, Time: blockTime, Validators: 3, Signers: 2, TotalPower: 60, SignedPower: 40}
+	if *got != *expected {
+		t.Errorf("expected %+v, got %+v", expected, got)
+	}
+	if len(validatorQueries) != 2 {
+		t.Errorf("expected the validator set to be paged twice, got %q", validatorQueries)
+	}
+}
+
+func TestThornodeClientGetRuneSupply(t *testing.T) {
+	var heights []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/cosmos/bank/v1beta1/supply/rune" {
+			http.NotFound(w, r)
+			return
+		}
+		heights = append(heights, r.Header.Get("x-cosmos-block-height"))
+		_, _ = w.Write([]byte(`{"amount":{"denom":"rune","amount":"42503672500000000"}}`))
+	}))
+	defer server.Close()
+
+	client, err := newThornodeClient(server.URL, server.URL)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	supply, err := client.AtHeight(12345).GetRuneSupply()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if supply != 42503672500000000 {
+		t.Errorf("expected supply 42503672500000000, got %d", supply)
+	}
+	if _, err := client.GetRuneSupply(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(heights) != 2 || heights[0] != "12345" || heights[1] != "" {
+		t.Errorf("expected only the pinned query to carry the height header, got %q", heights)
+	}
+}
+
+func TestThornodeClientGetRuneBalance(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/cosmos/bank/v1beta1/balances/thor1funded":
+			_, _ = w.Write([]byte(`{"balances":[{"denom":"btc/btc","amount":"5"},{"denom":"rune","amount":"250000000"}]}`))
+		case "/cosmos/bank/v1beta1/balances/thor1empty":
+			_, _ = w.Write([]byte(`{"balances":[]}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	client, err := newThornodeClient(server.URL, server.URL)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for address, expected := range map[string]int64{"thor1funded": 250000000, "thor1empty": 0} {
+		balance, err := client.GetRuneBalance(address)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if balance != expected {
+			t.Errorf("expected %s balance %d, got %d", address, expected, balance)
+		}
+	}
+}
@@ -0,0 +1,172 @@
+package events
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+
+	"github.com/rs/zerolog/log"
+	abci "github.com/tendermint/tendermint/abci/types"
+	ctypes "github.com/tendermint/tendermint/rpc/core/types"
+)
+
+// Synthetic event types emitted for every block and transaction, alongside the
+// THORChain events (e.g. slash, set_mimir, bond, outbound, security) they contain.
+const (
+	TypeNewBlock = "NewBlock"
+	TypeTx       = "Tx"
+)
+
+// Event is a single event emitted by THORChain at a given block height.
+type Event struct {
+	Height     int64             `json:"height"`
+	Type       string            `json:"type"`
+	TxHash     string            `json:"tx_hash,omitempty"` // set for events emitted by a transaction
+	Attributes map[string]string `json:"attributes,omitempty"`
+}
+
+// Handler processes events in the order they were emitted.
+type Handler func(Event)
+
+// Source delivers events to a handler until the context is cancelled or the
+// source is exhausted.
+type Source interface {
+	Run(ctx context.Context, handle Handler) error
+}
+
+// Filter returns a handler that only forwards events of the given types.
+func Filter(types []string, handle Handler) Handler {
+	wanted := make(map[string]bool, len(types))
+	for _, t := range types {
+		wanted[t] = true
+	}
+	return func(e Event) {
+		if wanted[e.Type] {
+			handle(e)
+		}
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// Block Conversion
+////////////////////////////////////////////////////////////////////////////////
+
+func attributes(attrs []abci.EventAttribute) map[string]string {
+	m := make(map[string]string, len(attrs))
+	for _, attr := range attrs {
+		m[string(attr.Key)] = string(attr.Value)
+	}
+	return m
+}
+
+func convert(height int64, txHash string, abciEvents []abci.Event) []Event {
+	var out []Event
+	for _, e := range abciEvents {
+		out = append(out, Event{Height: height, Type: e.Type, TxHash: txHash, Attributes: attributes(e.Attributes)})
+	}
+	return out
+}
+
+// blockEvents flattens a block and its results into events in execution order:
+// the block itself, begin block events, each transaction and its events, then
+// end block events.
+func blockEvents(block *ctypes.ResultBlock, results *ctypes.ResultBlockResults) []Event {
+	height := results.Height
+	out := []Event{{
+		Height: height,
+		Type:   TypeNewBlock,
+		Attributes: map[string]string{
+			"num_txs": strconv.Itoa(len(results.TxsResults)),
+			"time":    block.Block.Time.UTC().Format("2006-01-02T15:04:05Z"),
+		},
+	}}
+	out = append(out, convert(height, "", results.BeginBlockEvents)...)
+
+	for i, tx := range results.TxsResults {
+		var hash string
+		if i < len(block.Block.Txs) {
+			hash = fmt.Sprintf("%X", block.Block.Txs[i].Hash())
+		}
+		out = append(out, Event{
+			Height:     height,
+			Type:       TypeTx,
+			TxHash:     hash,
+			Attributes: map[string]string{"code": strconv.FormatUint(uint64(tx.Code), 10)},
+		})
+		out = append(out, convert(height, hash, tx.Events)...)
+	}
+
+	return append(out, convert(height, "", results.EndBlockEvents)...)
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// Record & Replay
+////////////////////////////////////////////////////////////////////////////////
+
+// Record returns a handler that writes every event to w as a JSON line before
+// passing it on, producing fixtures that can be replayed with NewReplay.
+func Record(w io.Writer, handle Handler) Handler {
+	encoder := json.NewEncoder(w)
+	return func(e Event) {
+		if err := encoder.Encode(e); err != nil {
+			// recording is best effort and must not interrupt alerting
+			log.Error().Err(err).Msg("failed to record event")
+		}
+		handle(e)
+	}
+}
+
+// recordingSource wraps a Source, recording every event it delivers.
+type recordingSource struct {
+	source Source
+	w      io.Writer
+}
+
+// Recording returns a Source that records every event delivered by source to w.
+func Recording(source Source, w io.Writer) Source {
+	return &recordingSource{source: source, w: w}
+}
+
+func (r *recordingSource) Run(ctx context.Context, handle Handler) error {
+	return r.source.Run(ctx, Record(r.w, handle))
+}
+
+// Replay is a Source serving events previously captured with Record.
+type Replay struct {
+	path string
+}
+
+// NewReplay returns a Source that replays the JSON lines fixture at path.
+func NewReplay(path string) *Replay {
+	return &Replay{path: path}
+}
+
+// Run delivers every recorded event to handle and returns once the fixture is exhausted.
+func (r *Replay) Run(ctx context.Context, handle Handler) error {
+	f, err := os.Open(r.path)
+	if err != nil {
+		return fmt.Errorf("failed to open event fixture: %w", err)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var e Event
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			return fmt.Errorf("invalid event on line %d of %s: %w", line, r.path, err)
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		handle(e)
+	}
+	return scanner.Err()
+}
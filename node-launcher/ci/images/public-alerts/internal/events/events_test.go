@@ -0,0 +1,227 @@
+package events
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	abci "github.com/tendermint/tendermint/abci/types"
+	ctypes "github.com/tendermint/tendermint/rpc/core/types"
+	"github.com/tendermint/tendermint/types"
+)
+
+func abciEvent(eventType string, kv ...string) abci.Event {
+	e := abci.Event{Type: eventType}
+	for i := 0; i+1 < len(kv); i += 2 {
+		e.Attributes = append(e.Attributes, abci.EventAttribute{Key: []byte(kv[i]), Value: []byte(kv[i+1])})
+	}
+	return e
+}
+
+func testBlock(height int64, txs ...[]abci.Event) (*ctypes.ResultBlock, *ctypes.ResultBlockResults) {
+	block := &types.Block{Header: types.Header{Height: height, Time: time.Unix(1700000000+height*6, 0)}}
+	results := &ctypes.ResultBlockResults{
+		Height:         height,
+		EndBlockEvents: []abci.Event{abciEvent("set_mimir", "key", "HALTETHCHAIN", "value", "1")},
+	}
+	for i, txEvents := range txs {
+		block.Txs = append(block.Txs, types.Tx{byte(i)})
+		results.TxsResults = append(results.TxsResults, &abci.ResponseDeliverTx{Events: txEvents})
+	}
+	return &ctypes.ResultBlock{Block: block}, results
+}
+
+func TestBlockEvents(t *testing.T) {
+	block, results := testBlock(10, []abci.Event{abciEvent("bond", "amount", "100", "bond_type", "bond_paid")})
+
+	got := blockEvents(block, results)
+	var kinds []string
+	for _, e := range got {
+		kinds = append(kinds, e.Type)
+		if e.Height != 10 {
+			t.Errorf("expected height 10, got %d", e.Height)
+		}
+	}
+	if want := []string{TypeNewBlock, TypeTx, "bond", "set_mimir"}; !reflect.DeepEqual(kinds, want) {
+		t.Fatalf("expected events %v, got %v", want, kinds)
+	}
+
+	txHash := got[1].TxHash
+	if txHash == "" || got[2].TxHash != txHash {
+		t.Errorf("expected tx events to carry the tx hash, got %q and %q", txHash, got[2].TxHash)
+	}
+	if got[2].Attributes["amount"] != "100" || got[3].Attributes["key"] != "HALTETHCHAIN" {
+		t.Errorf("unexpected attributes: %v %v", got[2].Attributes, got[3].Attributes)
+	}
+	if got[3].TxHash != "" {
+		t.Errorf("expected end block events to have no tx hash, got %q", got[3].TxHash)
+	}
+}
+
+func TestRecordReplay(t *testing.T) {
+	recorded := []Event{
+		{Height: 1, Type: TypeNewBlock},
+		{Height: 1, Type: "security", Attributes: map[string]string{"msg": "bad"}},
+		{Height: 2, Type: "set_mimir", Attributes: map[string]string{"key": "HALTTRADING", "value": "1"}},
+	}
+
+	var buf bytes.Buffer
+	var passed []Event
+	handle := Record(&buf, func(e Event) { passed = append(passed, e) })
+	for _, e := range recorded {
+		handle(e)
+	}
+	if !reflect.DeepEqual(passed, recorded) {
+		t.Fatalf("expected recorder to pass events through, got %v", passed)
+	}
+
+	path := filepath.Join(t.TempDir(), "events.jsonl")
+	if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var replayed []Event
+	err := NewReplay(path).Run(context.Background(), Filter([]string{"security", "set_mimir"}, func(e Event) {
+		replayed = append(replayed, e)
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(replayed, recorded[1:]) {
+		t.Errorf("expected filtered replay %v, got %v", recorded[1:], replayed)
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// Subscriber
+////////////////////////////////////////////////////////////////////////////////
+
+// fakeRPC serves a fixed set of new block headers and then goes silent, or closes
+// the subscription when closed is set. Block requests hang until cancelled when
+// hang is set.
+type fakeRPC struct {
+	headers []int64
+	closed  bool
+	hang    bool
+}
+
+func (f *fakeRPC) Start() error { return nil }
+func (f *fakeRPC) Stop() error  { return nil }
+
+func (f *fakeRPC) Subscribe(ctx context.Context, subscriber, query string, outCapacity ...int) (<-chan ctypes.ResultEvent, error) {
+	out := make(chan ctypes.ResultEvent, len(f.headers))
+	for _, h := range f.headers {
+		out <- ctypes.ResultEvent{Data: types.EventDataNewBlockHeader{Header: types.Header{Height: h}}}
+	}
+	if f.closed {
+		close(out)
+	}
+	return out, nil
+}
+
+func (f *fakeRPC) Block(ctx context.Context, height *int64) (*ctypes.ResultBlock, error) {
+	if f.hang {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	block, _ := testBlock(*height)
+	return block, nil
+}
+
+func (f *fakeRPC) BlockResults(ctx context.Context, height *int64) (*ctypes.ResultBlockResults, error) {
+	_, results := testBlock(*height)
+	return results, nil
+}
+
+func TestSubscriberReconnectResumes(t *testing.T) {
+	// the first session sees block 10 then stalls, the second resumes at block 13
+	sessions := []*fakeRPC{{headers: []int64{10}}, {headers: []int64{13}}}
+	var mu sync.Mutex
+	dials := 0
+
+	s := &Subscriber{
+		dial: func() (rpcClient, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			session := sessions[min(dials, len(sessions)-1)]
+			dials++
+			return session, nil
+		},
+		stallTimeout: 20 * time.Millisecond,
+		minBackoff:   time.Millisecond,
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var heights []int64
+	done := make(chan error)
+	go func() {
+		done <- s.Run(ctx, Filter([]string{TypeNewBlock}, func(e Event) {
+			heights = append(heights, e.Height)
+			if e.Height == 13 {
+				cancel()
+			}
+		}))
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("subscriber did not resume after reconnecting")
+	}
+
+	if want := []int64{10, 11, 12, 13}; !reflect.DeepEqual(heights, want) {
+		t.Errorf("expected blocks %v, got %v", want, heights)
+	}
+	if s.LastHeight() != 13 {
+		t.Errorf("expected last height 13, got %d", s.LastHeight())
+	}
+}
+
+func TestSubscriberClosedSubscription(t *testing.T) {
+	// a closed subscription ends the session without waiting for the stall timeout
+	s := &Subscriber{
+		dial:         func() (rpcClient, error) { return &fakeRPC{headers: []int64{10}, closed: true}, nil },
+		stallTimeout: time.Hour,
+		minBackoff:   time.Millisecond,
+	}
+
+	var heights []int64
+	delivered, err := s.stream(context.Background(), Filter([]string{TypeNewBlock}, func(e Event) {
+		heights = append(heights, e.Height)
+	}))
+	if !errors.Is(err, errClosed) {
+		t.Fatalf("expected a closed subscription error, got %v", err)
+	}
+	if !delivered || !reflect.DeepEqual(heights, []int64{10}) {
+		t.Errorf("expected block 10 to be delivered before the close, got %v", heights)
+	}
+}
+
+func TestSubscriberHungCatchUp(t *testing.T) {
+	// a block request that never returns trips the stall watchdog
+	s := &Subscriber{
+		dial:         func() (rpcClient, error) { return &fakeRPC{headers: []int64{10}, hang: true}, nil },
+		stallTimeout: 20 * time.Millisecond,
+		minBackoff:   time.Millisecond,
+	}
+
+	done := make(chan error)
+	go func() {
+		_, err := s.stream(context.Background(), func(e Event) {})
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		if !errors.Is(err, errStalled) {
+			t.Errorf("expected a stalled session, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("subscriber hung on a block request")
+	}
+}
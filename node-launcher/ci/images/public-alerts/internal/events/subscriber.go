@@ -0,0 +1,182 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	tmhttp "github.com/tendermint/tendermint/rpc/client/http"
+	ctypes "github.com/tendermint/tendermint/rpc/core/types"
+	"github.com/tendermint/tendermint/types"
+)
+
+const (
+	// defaultStallTimeout is how long to wait for a new block before reconnecting.
+	defaultStallTimeout = time.Minute
+	// minReconnectBackoff and maxReconnectBackoff bound the delay between reconnect attempts.
+	minReconnectBackoff = time.Second
+	maxReconnectBackoff = time.Minute
+	// maxCatchUpBlocks bounds how many missed blocks are replayed after a reconnect.
+	maxCatchUpBlocks = 600 // ~1 hour
+	// rpcTimeout bounds each block and block results request.
+	rpcTimeout = 30 * time.Second
+)
+
+var (
+	errStalled = errors.New("no new block received")
+	errClosed  = errors.New("subscription closed")
+)
+
+// rpcClient is the subset of the Tendermint RPC client used by the Subscriber.
+type rpcClient interface {
+	Start() error
+	Stop() error
+	Subscribe(ctx context.Context, subscriber, query string, outCapacity ...int) (<-chan ctypes.ResultEvent, error)
+	Block(ctx context.Context, height *int64) (*ctypes.ResultBlock, error)
+	BlockResults(ctx context.Context, height *int64) (*ctypes.ResultBlockResults, error)
+}
+
+// Subscriber is a Source streaming events from a THORNode Tendermint RPC websocket.
+//
+// It subscribes to new block headers and reads the events of every block from
+// its block results, so no block is skipped between notifications. When the
+// connection drops or stalls it reconnects with backoff and resumes from the
+// block after the last one processed.
+type Subscriber struct {
+	dial         func() (rpcClient, error)
+	lastHeight   int64
+	stallTimeout time.Duration
+	minBackoff   time.Duration
+}
+
+// NewSubscriber creates a Subscriber for the given RPC endpoint. Events are
+// delivered from fromHeight when set, otherwise from the next block produced.
+func NewSubscriber(rpcURL string, fromHeight int64) *Subscriber {
+	return &Subscriber{
+		dial: func() (rpcClient, error) {
+			client, err := tmhttp.NewWithClient(rpcURL, "/websocket", &http.Client{Timeout: rpcTimeout})
+			if err != nil {
+				return nil, err
+			}
+			return client, nil
+		},
+		lastHeight:   max(fromHeight-1, 0),
+		stallTimeout: defaultStallTimeout,
+		minBackoff:   minReconnectBackoff,
+	}
+}
+
+// LastHeight returns the height of the last block whose events were delivered.
+func (s *Subscriber) LastHeight() int64 {
+	return s.lastHeight
+}
+
+// Run streams events to handle until ctx is cancelled, reconnecting as needed.
+func (s *Subscriber) Run(ctx context.Context, handle Handler) error {
+	backoff := s.minBackoff
+	for {
+		delivered, err := s.stream(ctx, handle)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if delivered {
+			backoff = s.minBackoff
+		}
+		log.Warn().Err(err).Int64("lastHeight", s.lastHeight).Msgf("event subscription interrupted, reconnecting in %s", backoff)
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, maxReconnectBackoff)
+	}
+}
+
+// stream runs a single websocket session, reporting whether any block was delivered.
+func (s *Subscriber) stream(ctx context.Context, handle Handler) (bool, error) {
+	client, err := s.dial()
+	if err != nil {
+		return false, fmt.Errorf("failed to create RPC client: %w", err)
+	}
+	if err := client.Start(); err != nil {
+		return false, fmt.Errorf("failed to start websocket: %w", err)
+	}
+	defer func() {
+		if err := client.Stop(); err != nil {
+			log.Debug().Err(err).Msg("failed to stop websocket")
+		}
+	}()
+
+	headers, err := client.Subscribe(ctx, "public-alerts", types.QueryForEvent(types.EventNewBlockHeader).String(), 100)
+	if err != nil {
+		return false, fmt.Errorf("failed to subscribe: %w", err)
+	}
+
+	// the stall watchdog also runs while catching up, cancelling a hung block request
+	session, cancel := context.WithCancel(ctx)
+	defer cancel()
+	stall := time.AfterFunc(s.stallTimeout, cancel)
+	defer stall.Stop()
+
+	delivered := false
+	for {
+		select {
+		case <-session.Done():
+			if ctx.Err() != nil {
+				return delivered, ctx.Err()
+			}
+			return delivered, errStalled
+		case result, ok := <-headers:
+			if !ok {
+				// the client closes the channel when the websocket drops
+				return delivered, errClosed
+			}
+			header, ok := result.Data.(types.EventDataNewBlockHeader)
+			if !ok {
+				continue
+			}
+			if err := s.catchUp(session, client, header.Header.Height, handle, stall); err != nil {
+				if ctx.Err() == nil && session.Err() != nil {
+					return delivered, fmt.Errorf("%w: %v", errStalled, err)
+				}
+				return delivered, err
+			}
+			delivered = true
+			stall.Reset(s.stallTimeout)
+		}
+	}
+}
+
+// catchUp delivers the events of every block after the last processed one up to tip,
+// resetting the stall watchdog after each block.
+func (s *Subscriber) catchUp(ctx context.Context, client rpcClient, tip int64, handle Handler, stall *time.Timer) error {
+	if s.lastHeight == 0 {
+		s.lastHeight = tip - 1
+	}
+	if tip-s.lastHeight > maxCatchUpBlocks {
+		log.Warn().Int64("from", s.lastHeight+1).Int64("to", tip-maxCatchUpBlocks).Msg("skipping blocks beyond catch up window")
+		s.lastHeight = tip - maxCatchUpBlocks
+	}
+
+	for height := s.lastHeight + 1; height <= tip; height++ {
+		h := height
+		block, err := client.Block(ctx, &h)
+		if err != nil {
+			return fmt.Errorf("failed to fetch block %d: %w", h, err)
+		}
+		results, err := client.BlockResults(ctx, &h)
+		if err != nil {
+			return fmt.Errorf("failed to fetch block results %d: %w", h, err)
+		}
+		for _, e := range blockEvents(block, results) {
+			handle(e)
+		}
+		s.lastHeight = h
+		stall.Reset(s.stallTimeout)
+	}
+	return nil
+}
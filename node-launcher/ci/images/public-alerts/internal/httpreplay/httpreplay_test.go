@@ -0,0 +1,100 @@
+package httpreplay
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func get(t *testing.T, client *http.Client, url string) (int, string) {
+	t.Helper()
+	resp, err := client.Get(url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	return resp.StatusCode, string(body)
+}
+
+func TestRecordReplay(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch {
+		case req.Method == http.MethodPost:
+			var rpc rpcRequest
+			_ = json.NewDecoder(req.Body).Decode(&rpc)
+			_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":` + string(rpc.ID) + `,"result":{"method":"` + rpc.Method + `"}}`))
+		case req.URL.Path == "/api/thorchain/nodes":
+			_, _ = w.Write([]byte(`[ {"node_address": "thor1` + req.URL.Query().Get("height") + `"} ]`))
+		default:
+			http.Error(w, "not found", http.StatusNotFound)
+		}
+	}))
+	defer upstream.Close()
+
+	recorder, err := NewRecorder(map[string]string{
+		ServiceThornode:    upstream.URL + "/api",
+		ServiceThornodeRPC: upstream.URL + "/rpc",
+	}, http.DefaultTransport)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := &http.Client{Transport: recorder}
+
+	_, nodes := get(t, client, upstream.URL+"/api/thorchain/nodes?height=1")
+	get(t, client, upstream.URL+"/api/thorchain/missing")
+	resp, err := client.Post(upstream.URL+"/rpc", "application/json", strings.NewReader(`{"jsonrpc":"2.0","id":1,"method":"status","params":{}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	path := filepath.Join(t.TempDir(), "scenario.json")
+	if err := recorder.Scenario("test").Save(path); err != nil {
+		t.Fatal(err)
+	}
+	scenario, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(scenario.Interactions) != 3 {
+		t.Fatalf("expected 3 interactions, got %+v", scenario.Interactions)
+	}
+
+	server := NewServer(scenario)
+	defer server.Close()
+	replay := &http.Client{}
+
+	status, body := get(t, replay, server.URL(ServiceThornode)+"/thorchain/nodes?height=1")
+	if status != http.StatusOK || compactJSON([]byte(body)) != `[{"node_address":"thor11"}]` {
+		t.Errorf("unexpected replayed nodes %d %s (recorded %s)", status, body, nodes)
+	}
+	if status, body = get(t, replay, server.URL(ServiceThornode)+"/thorchain/missing"); status != http.StatusNotFound || body != "not found\n" {
+		t.Errorf("expected recorded 404 text, got %d %q", status, body)
+	}
+
+	// JSON-RPC responses are matched by method and answer with the request id
+	resp, err = replay.Post(server.URL(ServiceThornodeRPC), "application/json", bytes.NewReader([]byte(`{"jsonrpc":"2.0","id":7,"method":"status","params":{}}`)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var rpcResp map[string]json.RawMessage
+	_ = json.NewDecoder(resp.Body).Decode(&rpcResp)
+	resp.Body.Close()
+	if string(rpcResp["id"]) != "7" || string(rpcResp["result"]) != `{"method":"status"}` {
+		t.Errorf("unexpected rpc response %s %s", rpcResp["id"], rpcResp["result"])
+	}
+
+	if status, _ = get(t, replay, server.URL(ServiceThornode)+"/thorchain/nodes?height=2"); status != http.StatusNotFound {
+		t.Errorf("expected 404 for unrecorded request, got %d", status)
+	}
+	if want := []string{"thornode GET /thorchain/nodes?height=2"}; !reflect.DeepEqual(server.Unmatched(), want) {
+		t.Errorf("expected unmatched %v, got %v", want, server.Unmatched())
+	}
+}
@@ -0,0 +1,112 @@
+package httpreplay
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+// Recorder is an http.RoundTripper capturing the responses of requests made to
+// the registered services. Requests to other hosts pass through unrecorded.
+type Recorder struct {
+	next     http.RoundTripper
+	services map[string]*url.URL
+
+	mu       sync.Mutex
+	scenario Scenario
+}
+
+// NewRecorder creates a Recorder for the given service base URLs, forwarding
+// requests to next.
+func NewRecorder(services map[string]string, next http.RoundTripper) (*Recorder, error) {
+	parsed := make(map[string]*url.URL, len(services))
+	for name, raw := range services {
+		if raw == "" {
+			continue
+		}
+		u, err := url.Parse(raw)
+		if err != nil {
+			return nil, err
+		}
+		parsed[name] = u
+	}
+	return &Recorder{next: next, services: parsed}, nil
+}
+
+// match returns the service serving u and the request path relative to it.
+func (r *Recorder) match(u *url.URL) (string, string, bool) {
+	for name, base := range r.services {
+		basePath := strings.TrimSuffix(base.Path, "/")
+		if u.Scheme != base.Scheme || u.Host != base.Host || !strings.HasPrefix(u.Path, basePath) {
+			continue
+		}
+		path := strings.TrimPrefix(u.Path, basePath)
+		if u.RawQuery != "" {
+			path += "?" + u.RawQuery
+		}
+		return name, path, true
+	}
+	return "", "", false
+}
+
+// RoundTrip forwards the request and records the response.
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	service, path, ok := r.match(req.URL)
+	if !ok {
+		return r.next.RoundTrip(req)
+	}
+
+	var reqBody []byte
+	if req.Body != nil {
+		var err error
+		if reqBody, err = io.ReadAll(req.Body); err != nil {
+			return nil, err
+		}
+		req.Body.Close()
+		req.Body = io.NopCloser(bytes.NewReader(reqBody))
+	}
+
+	resp, err := r.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	interaction := Interaction{
+		Service: service,
+		Method:  req.Method,
+		Path:    path,
+		Status:  resp.StatusCode,
+		Body:    jsonBody(body),
+	}
+	if rpc, ok := parseRPCRequest(reqBody); ok {
+		interaction.Path = ""
+		interaction.RPC = rpc.Method
+		interaction.Params = json.RawMessage(compactJSON(rpc.Params))
+	}
+
+	r.mu.Lock()
+	r.scenario.add(interaction)
+	r.mu.Unlock()
+
+	return resp, nil
+}
+
+// Scenario returns a copy of the interactions recorded so far.
+func (r *Recorder) Scenario(description string) *Scenario {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return &Scenario{
+		Description:  description,
+		Interactions: append([]Interaction(nil), r.scenario.Interactions...),
+	}
+}
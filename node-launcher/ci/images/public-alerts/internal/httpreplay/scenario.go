@@ -0,0 +1,116 @@
+// Package httpreplay records API responses into scenario fixture files and serves
+// them back from local test servers, so monitors can be exercised end to end
+// against captured THORNode, Midgard, Nine Realms and GitHub responses.
+package httpreplay
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// Services recorded by the harness. Each service is served from its own base URL.
+const (
+	ServiceThornode    = "thornode"
+	ServiceThornodeRPC = "thornode-rpc"
+	ServiceMidgard     = "midgard"
+	ServiceNineRealms  = "ninerealms"
+	ServiceGithub      = "github"
+)
+
+// Interaction is a single recorded request and its response.
+type Interaction struct {
+	Service string `json:"service"`
+	Method  string `json:"method"`
+	// Path is the request path relative to the service base URL, including the query.
+	Path string `json:"path,omitempty"`
+	// RPC and Params identify Tendermint JSON-RPC calls, which all share one path.
+	RPC    string          `json:"rpc,omitempty"`
+	Params json.RawMessage `json:"params,omitempty"`
+
+	Status int `json:"status"`
+	// Body is the JSON response. Non-JSON responses are stored as a JSON string.
+	Body json.RawMessage `json:"body"`
+}
+
+// key identifies the request an interaction answers within its service.
+func (i Interaction) key() string {
+	if i.RPC != "" {
+		return fmt.Sprintf("rpc %s %s", i.RPC, compactJSON(i.Params))
+	}
+	return fmt.Sprintf("%s %s", i.Method, i.Path)
+}
+
+// Scenario is a set of interactions captured together, e.g. during a churn.
+type Scenario struct {
+	Description  string        `json:"description"`
+	Interactions []Interaction `json:"interactions"`
+}
+
+// add appends the interaction, replacing an earlier response to the same request.
+func (s *Scenario) add(interaction Interaction) {
+	for i, existing := range s.Interactions {
+		if existing.Service == interaction.Service && existing.key() == interaction.key() {
+			s.Interactions[i] = interaction
+			return
+		}
+	}
+	s.Interactions = append(s.Interactions, interaction)
+}
+
+// Load reads a scenario fixture file.
+func Load(path string) (*Scenario, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var scenario Scenario
+	if err := json.Unmarshal(data, &scenario); err != nil {
+		return nil, fmt.Errorf("failed to parse scenario %s: %w", path, err)
+	}
+	return &scenario, nil
+}
+
+// Save writes the scenario to a fixture file.
+func (s *Scenario) Save(path string) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+// jsonBody returns body as raw JSON, quoting it as a string if it isn't JSON.
+func jsonBody(body []byte) json.RawMessage {
+	if json.Valid(body) {
+		return json.RawMessage(compactJSON(body))
+	}
+	quoted, _ := json.Marshal(string(body))
+	return quoted
+}
+
+// compactJSON returns data without insignificant whitespace, or as is if it isn't JSON.
+func compactJSON(data []byte) string {
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, data); err != nil {
+		return string(data)
+	}
+	return buf.String()
+}
+
+// rpcRequest is the envelope of a Tendermint JSON-RPC request.
+type rpcRequest struct {
+	ID     json.RawMessage `json:"id"`
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params"`
+}
+
+// parseRPCRequest returns the JSON-RPC request in body, if it is one.
+func parseRPCRequest(body []byte) (rpcRequest, bool) {
+	var req rpcRequest
+	if len(body) == 0 || json.Unmarshal(body, &req) != nil || req.Method == "" {
+		return rpcRequest{}, false
+	}
+	return req, true
+}
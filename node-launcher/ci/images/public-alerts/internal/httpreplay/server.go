@@ -0,0 +1,116 @@
+package httpreplay
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+)
+
+// Server replays a scenario, serving each service from its own local test server.
+// Requests without a recorded response get a 404 and are reported by Unmatched.
+type Server struct {
+	mu        sync.Mutex
+	servers   map[string]*httptest.Server
+	responses map[string]map[string]Interaction // service -> request key -> interaction
+	unmatched []string
+}
+
+// NewServer creates a Server replaying the scenario.
+func NewServer(scenario *Scenario) *Server {
+	s := &Server{
+		servers:   make(map[string]*httptest.Server),
+		responses: make(map[string]map[string]Interaction),
+	}
+	for _, interaction := range scenario.Interactions {
+		if s.responses[interaction.Service] == nil {
+			s.responses[interaction.Service] = make(map[string]Interaction)
+		}
+		s.responses[interaction.Service][interaction.key()] = interaction
+	}
+	return s
+}
+
+// URL returns the base URL serving the service, starting its server if needed.
+func (s *Server) URL(service string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if server, ok := s.servers[service]; ok {
+		return server.URL
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		s.serve(service, w, req)
+	}))
+	s.servers[service] = server
+	return server.URL
+}
+
+// Unmatched returns the requests that had no recorded response.
+func (s *Server) Unmatched() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.unmatched...)
+}
+
+// Close shuts down every service server.
+func (s *Server) Close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, server := range s.servers {
+		server.Close()
+	}
+}
+
+func (s *Server) serve(service string, w http.ResponseWriter, req *http.Request) {
+	reqBody, err := io.ReadAll(req.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	request := Interaction{Method: req.Method, Path: req.URL.RequestURI()}
+	rpc, isRPC := parseRPCRequest(reqBody)
+	if isRPC {
+		request.RPC = rpc.Method
+		request.Params = rpc.Params
+	}
+
+	s.mu.Lock()
+	interaction, ok := s.responses[service][request.key()]
+	if !ok {
+		s.unmatched = append(s.unmatched, fmt.Sprintf("%s %s", service, request.key()))
+	}
+	s.mu.Unlock()
+	if !ok {
+		http.Error(w, "no recorded response", http.StatusNotFound)
+		return
+	}
+
+	body := []byte(interaction.Body)
+	var text string
+	if json.Unmarshal(body, &text) == nil {
+		body = []byte(text)
+	} else if isRPC {
+		body = withRPCID(body, rpc.ID)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(interaction.Status)
+	_, _ = w.Write(body)
+}
+
+// withRPCID sets the JSON-RPC response id to match the request, as clients verify it.
+func withRPCID(body []byte, id json.RawMessage) []byte {
+	var envelope map[string]json.RawMessage
+	if json.Unmarshal(body, &envelope) != nil || id == nil {
+		return body
+	}
+	envelope["id"] = id
+	rewritten, err := json.Marshal(envelope)
+	if err != nil {
+		return body
+	}
+	return rewritten
+}
@@ -0,0 +1,130 @@
+package monitor
+
+import (
+	"fmt"
+	"public-alerts/internal/chains"
+	"public-alerts/internal/common"
+	"public-alerts/internal/config"
+	"public-alerts/internal/notify"
+	"sort"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+)
+
+// ChainDaemonMonitor checks the daemons the node's bifrost observes through their
+// chain adapters. It alerts when a daemon is unreachable for the configured number of
+// polls, is syncing, has no peers or trails the median height the active nodes
+// observe for its chain, and once more when all of a daemon's issues cleared.
+type ChainDaemonMonitor struct {
+	client   common.ThornodeDataFetcher
+	adapters []chains.ChainAdapter
+	failures map[string]int             // consecutive failed polls, by chain
+	tripped  map[string]map[string]bool // issues already alerted, by chain
+}
+
+func NewChainDaemonMonitor(client common.ThornodeDataFetcher, adapters []chains.ChainAdapter) *ChainDaemonMonitor {
+	return &ChainDaemonMonitor{
+		client:   client,
+		adapters: adapters,
+		failures: make(map[string]int),
+		tripped:  make(map[string]map[string]bool),
+	}
+}
+
+func (cdm *ChainDaemonMonitor) Name() string {
+	return "ChainDaemonMonitor"
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// Helpers
+////////////////////////////////////////////////////////////////////////////////
+
+// daemonIssues returns the issues of a reachable daemon by kind. A network height of
+// zero or a limit of zero skips the height comparison.
+func daemonIssues(status *chains.ChainStatus, networkHeight int64, maxBehind int) map[string]string {
+	issues := make(map[string]string)
+	if status.Syncing {
+		issues["syncing"] = fmt.Sprintf("> **Syncing:** at height %d", status.Height)
+	}
+	if status.Peers == 0 {
+		issues["peers"] = "> **Peers:** none connected"
+	}
+	if networkHeight > 0 && maxBehind > 0 && networkHeight-status.Height > int64(maxBehind) {
+		issues["behind"] = fmt.Sprintf("> **Behind:** %d blocks (local %d, network %d, limit %d)",
+			networkHeight-status.Height, status.Height, networkHeight, maxBehind)
+	}
+	return issues
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// Check
+////////////////////////////////////////////////////////////////////////////////
+
+func (cdm *ChainDaemonMonitor) Check() ([]notify.Alert, error) {
+	log.Info().Msg("Checking chain daemons...")
+	cfg := config.Get()
+	dcfg := cfg.ChainDaemonMonitor
+
+	client, height, err := common.PinLatestHeight(cdm.client)
+	if err != nil {
+		return nil, err
+	}
+	nodes, err := client.GetNodes()
+	if err != nil {
+		return nil, err
+	}
+	network := observationMedians(nodes)
+
+	var alerts []notify.Alert
+	for _, adapter := range cdm.adapters {
+		chain := adapter.Chain()
+		issues := make(map[string]string)
+		status, err := adapter.Status()
+		if err != nil {
+			cdm.failures[chain]++
+			log.Error().Err(err).Str("chain", chain).Int("failures", cdm.failures[chain]).Msg("failed to query chain daemon")
+			if cdm.failures[chain] < dcfg.UnreachablePolls {
+				continue
+			}
+			// the daemon's other issues are unknown while it is unreachable and stay tripped
+			for kind := range cdm.tripped[chain] {
+				issues[kind] = ""
+			}
+			issues["unreachable"] = fmt.Sprintf("> **Unreachable:** %d polls, %s", cdm.failures[chain], err)
+		} else {
+			cdm.failures[chain] = 0
+			issues = daemonIssues(status, int64(network[chain]), dcfg.MaxBlocksBehind[chain])
+		}
+
+		var kinds []string
+		for kind := range issues {
+			if !cdm.tripped[chain][kind] {
+				kinds = append(kinds, kind)
+			}
+		}
+		sort.Strings(kinds)
+
+		var msg string
+		switch {
+		case len(kinds) > 0:
+			lines := make([]string, 0, len(kinds))
+			for _, kind := range kinds {
+				lines = append(lines, issues[kind])
+			}
+			msg = fmt.Sprintf("### Chain Daemon Unhealthy: %s\n%s", chain, strings.Join(lines, "\n"))
+		case len(issues) == 0 && len(cdm.tripped[chain]) > 0:
+			msg = fmt.Sprintf("### Chain Daemon Recovered: %s\n> **Height:** %d (network %.0f)", chain, status.Height, network[chain])
+		}
+		if msg != "" {
+			alerts = append(alerts, notify.Alert{Webhooks: cfg.Webhooks.Activity, Message: msg, Height: height})
+		}
+
+		tripped := make(map[string]bool, len(issues))
+		for kind := range issues {
+			tripped[kind] = true
+		}
+		cdm.tripped[chain] = tripped
+	}
+	return alerts, nil
+}
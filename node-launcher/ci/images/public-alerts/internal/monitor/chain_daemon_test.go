@@ -0,0 +1,133 @@
+package monitor
+
+import (
+	"errors"
+	"public-alerts/internal/chains"
+	"public-alerts/internal/common"
+	"reflect"
+	"sort"
+	"testing"
+
+	openapi "gitlab.com/thorchain/thornode/openapi/gen"
+)
+
+type fakeAdapter struct {
+	chain    string
+	status   chains.ChainStatus
+	balances map[string]int64 // by address/router/asset
+	err      error
+}
+
+func (f *fakeAdapter) Chain() string { return f.chain }
+
+func (f *fakeAdapter) Status() (*chains.ChainStatus, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	status := f.status
+	return &status, nil
+}
+
+func (f *fakeAdapter) Balance(address, router, asset string) (int64, error) {
+	if f.err != nil {
+		return 0, f.err
+	}
+	balance, ok := f.balances[address+"/"+router+"/"+asset]
+	if !ok {
+		return 0, errors.New("unknown balance")
+	}
+	return balance, nil
+}
+
+func TestDaemonIssues(t *testing.T) {
+	tests := []struct {
+		name          string
+		status        chains.ChainStatus
+		networkHeight int64
+		expected      []string
+	}{
+		{"healthy", chains.ChainStatus{Height: 100, Peers: 8}, 102, nil},
+		{"unknown network height", chains.ChainStatus{Height: 100, Peers: 8}, 0, nil},
+		{"syncing without peers", chains.ChainStatus{Height: 100, Syncing: true}, 100, []string{"peers", "syncing"}},
+		{"behind", chains.ChainStatus{Height: 100, Peers: 8}, 104, []string{"behind"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status := tt.status
+			var kinds []string
+			for kind := range daemonIssues(&status, tt.networkHeight, 3) {
+				kinds = append(kinds, kind)
+			}
+			sort.Strings(kinds)
+			if !reflect.DeepEqual(kinds, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, kinds)
+			}
+		})
+	}
+}
+
+func TestChainDaemonMonitorCheck(t *testing.T) {
+	client := common.NewFakeClient()
+	client.Height = 1000
+	for _, address := range []string{"thor1node1", "thor1node2", "thor1node3"} {
+		client.Nodes = append(client.Nodes, observingNode(address, "Active",
+			openapi.ChainHeight{Chain: "BTC", Height: 850000}, openapi.ChainHeight{Chain: "ETH", Height: 20000000}))
+	}
+	btc := &fakeAdapter{chain: "BTC", status: chains.ChainStatus{Height: 850000, Peers: 8}}
+	eth := &fakeAdapter{chain: "ETH", status: chains.ChainStatus{Height: 20000000, Peers: 50}}
+	cdm := NewChainDaemonMonitor(client, []chains.ChainAdapter{btc, eth})
+
+	check := func(expected ...string) {
+		t.Helper()
+		alerts, err := cdm.Check()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		var messages []string
+		for _, alert := range alerts {
+			messages = append(messages, alert.Message)
+			if alert.Height != 1000 {
+				t.Errorf("expected alert at height 1000, got %d", alert.Height)
+			}
+		}
+		if !reflect.DeepEqual(messages, expected) {
+			t.Errorf("expected %q, got %q", expected, messages)
+		}
+	}
+
+	check()
+
+	// btc falls behind, then also loses its peers
+	btc.status = chains.ChainStatus{Height: 849990, Peers: 8}
+	check("### Chain Daemon Unhealthy: BTC\n> **Behind:** 10 blocks (local 849990, network 850000, limit 3)")
+	check()
+	btc.status.Peers = 0
+	check("### Chain Daemon Unhealthy: BTC\n> **Peers:** none connected")
+
+	// eth is only reported unreachable after three failed polls
+	eth.err = errors.New("unavailable")
+	check()
+	check()
+	check("### Chain Daemon Unhealthy: ETH\n> **Unreachable:** 3 polls, unavailable")
+	check()
+
+	// while btc is unreachable its earlier issues stay tripped
+	btc.err = errors.New("unavailable")
+	check()
+	check()
+	check("### Chain Daemon Unhealthy: BTC\n> **Unreachable:** 3 polls, unavailable")
+	btc.err = nil
+	check()
+
+	btc.status = chains.ChainStatus{Height: 850001, Peers: 8}
+	eth.err = nil
+	check(
+		"### Chain Daemon Recovered: BTC\n> **Height:** 850001 (network 850000)",
+		"### Chain Daemon Recovered: ETH\n> **Height:** 20000000 (network 20000000)",
+	)
+
+	client.Err = errors.New("unavailable")
+	if _, err := cdm.Check(); err == nil {
+		t.Error("expected error when thornode is unavailable")
+	}
+}
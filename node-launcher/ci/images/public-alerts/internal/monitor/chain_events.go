@@ -0,0 +1,98 @@
+package monitor
+
+import (
+	"fmt"
+	"public-alerts/internal/config"
+	"public-alerts/internal/events"
+	"public-alerts/internal/notify"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+)
+
+// ChainEventMonitor alerts on THORChain events as soon as the block emitting them
+// is produced: security events, pool slashes and large bonds. Mimir changes are left to
+// the MimirMonitor.
+type ChainEventMonitor struct{}
+
+func NewChainEventMonitor() *ChainEventMonitor {
+	return &ChainEventMonitor{}
+}
+
+func (cem *ChainEventMonitor) Name() string {
+	return "ChainEventMonitor"
+}
+
+func (cem *ChainEventMonitor) EventTypes() []string {
+	return []string{"security", "slash", "bond"}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// Helpers
+////////////////////////////////////////////////////////////////////////////////
+
+func explorerTxLink(cfg config.Config, txID string) string {
+	return fmt.Sprintf("%s/tx/%s", cfg.Endpoints.ExplorerURL, txID)
+}
+
+// sortedAttributes returns the event attributes as "key: value" lines, skipping excluded keys.
+func sortedAttributes(attrs map[string]string, exclude ...string) []string {
+	skip := make(map[string]bool, len(exclude))
+	for _, k := range exclude {
+		skip[k] = true
+	}
+	var lines []string
+	for k, v := range attrs {
+		if !skip[k] {
+			lines = append(lines, fmt.Sprintf("> **%s:** `%s`", k, v))
+		}
+	}
+	sort.Strings(lines)
+	return lines
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// HandleEvent
+////////////////////////////////////////////////////////////////////////////////
+
+func (cem *ChainEventMonitor) HandleEvent(event events.Event) ([]notify.Alert, error) {
+	cfg := config.Get()
+	attrs := event.Attributes
+
+	switch event.Type {
+	case "security":
+		msg := fmt.Sprintf("### Security Event\n> **Msg:** `%s`", attrs["msg"])
+		if id := attrs["id"]; id != "" {
+			msg += fmt.Sprintf("\n> **Tx:** %s", explorerTxLink(cfg, id))
+		}
+		msgs := append([]string{msg}, sortedAttributes(attrs, "msg", "id")...)
+		return []notify.Alert{{Webhooks: cfg.Webhooks.Security, Message: strings.Join(msgs, "\n"), Height: int(event.Height)}}, nil
+
+	case "slash":
+		msgs := append([]string{fmt.Sprintf("### Pool Slashed\n> **Pool:** `%s`", attrs["pool"])}, sortedAttributes(attrs, "pool")...)
+		return []notify.Alert{{Webhooks: cfg.Webhooks.Activity, Message: strings.Join(msgs, "\n"), Height: int(event.Height)}}, nil
+
+	case "bond":
+		bondType := attrs["bond_type"]
+		if bondType != "bond_paid" && bondType != "bond_returned" {
+			return nil, nil
+		}
+		amount, err := strconv.ParseInt(attrs["amount"], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid bond amount %q: %w", attrs["amount"], err)
+		}
+		if amount < cfg.ChainEventMonitor.BondAlertThreshold {
+			return nil, nil
+		}
+		msg := fmt.Sprintf("### Large Bond\n> **Type:** `%s`\n> **Amount:** %.0f RUNE", bondType, float64(amount)/1e8)
+		if id := attrs["id"]; id != "" {
+			msg += fmt.Sprintf("\n> **Tx:** %s", explorerTxLink(cfg, id))
+		}
+		return []notify.Alert{{Webhooks: cfg.Webhooks.Activity, Message: msg, Height: int(event.Height)}}, nil
+	}
+
+	log.Debug().Str("type", event.Type).Msg("ignoring unexpected event type")
+	return nil, nil
+}
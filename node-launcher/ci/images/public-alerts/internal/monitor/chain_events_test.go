@@ -0,0 +1,58 @@
+package monitor
+
+import (
+	"context"
+	"public-alerts/internal/events"
+	"public-alerts/internal/notify"
+	"strings"
+	"testing"
+)
+
+func TestChainEventMonitorReplay(t *testing.T) {
+	alertQueue := make(chan notify.Alert, 100)
+	handle := dispatchEvents([]EventMonitor{NewChainEventMonitor()}, alertQueue)
+
+	if err := events.NewReplay("testdata/events/chain_events.jsonl").Run(context.Background(), handle); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	close(alertQueue)
+
+	var alerts []notify.Alert
+	for alert := range alertQueue {
+		alerts = append(alerts, alert)
+	}
+
+	// the small bond, the bond reward and the outbound are not alerted on, mimir changes
+	// are left to the mimir monitor
+	expected := []struct {
+		height   int
+		contains []string
+	}{
+		{15210001, []string{"### Large Bond", "`bond_returned`", "800000 RUNE", "/tx/1F2E3D4C"}},
+		{15210002, []string{"### Pool Slashed", "**Pool:** `BTC.BTC`", "**THOR.RUNE:** `480000000000`"}},
+		{15210002, []string{"### Security Event", "insolvency detected", "/tx/B3A2C1D0"}},
+	}
+	if len(alerts) != len(expected) {
+		t.Fatalf("expected %d alerts, got %d: %v", len(expected), len(alerts), alerts)
+	}
+	for i, want := range expected {
+		if alerts[i].Height != want.height {
+			t.Errorf("alert %d: expected height %d, got %d", i, want.height, alerts[i].Height)
+		}
+		for _, s := range want.contains {
+			if !strings.Contains(alerts[i].Message, s) {
+				t.Errorf("alert %d: expected message to contain %q, got %s", i, s, alerts[i].Message)
+			}
+		}
+	}
+}
+
+func TestChainEventMonitorInvalidBond(t *testing.T) {
+	_, err := NewChainEventMonitor().HandleEvent(events.Event{
+		Type:       "bond",
+		Attributes: map[string]string{"bond_type": "bond_paid", "amount": "not-a-number"},
+	})
+	if err == nil {
+		t.Error("expected error for malformed bond amount")
+	}
+}
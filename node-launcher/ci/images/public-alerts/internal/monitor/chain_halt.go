@@ -0,0 +1,205 @@
+package monitor
+
+import (
+	"fmt"
+	"public-alerts/internal/common"
+	"public-alerts/internal/config"
+	"public-alerts/internal/notify"
+	"sort"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+	openapi "gitlab.com/thorchain/thornode/openapi/gen"
+)
+
+// InsolvencySource provides the latest solvency findings, e.g. a SolvencyMonitor.
+type InsolvencySource interface {
+	LastInsolvencies() []Insolvency
+}
+
+// ChainHaltMonitor alerts when a chain halts or its trading is paused, and again
+// when it resumes. Each halt is reported with its cause from the halt mimir keys in
+// effect, and solvency auto-halts include the current solvency findings.
+type ChainHaltMonitor struct {
+	client      common.ThornodeDataFetcher
+	solvency    InsolvencySource // optional
+	initialized bool
+	last        map[string]chainHaltStatus // by chain
+}
+
+// chainHaltStatus is the halt state of a chain as reported by its inbound address.
+type chainHaltStatus struct {
+	Halted        bool
+	TradingPaused bool
+	GlobalPaused  bool
+}
+
+func (s chainHaltStatus) any() bool {
+	return s.Halted || s.TradingPaused || s.GlobalPaused
+}
+
+func NewChainHaltMonitor(client common.ThornodeDataFetcher, solvency InsolvencySource) *ChainHaltMonitor {
+	return &ChainHaltMonitor{
+		client:   client,
+		solvency: solvency,
+		last:     make(map[string]chainHaltStatus),
+	}
+}
+
+func (chm *ChainHaltMonitor) Name() string {
+	return "ChainHaltMonitor"
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// Helpers
+////////////////////////////////////////////////////////////////////////////////
+
+func chainHaltStatuses(addresses []openapi.InboundAddress) map[string]chainHaltStatus {
+	statuses := make(map[string]chainHaltStatus, len(addresses))
+	for _, address := range addresses {
+		statuses[address.GetChain()] = chainHaltStatus{
+			Halted:        address.Halted,
+			TradingPaused: address.GetChainTradingPaused(),
+			GlobalPaused:  address.GetGlobalTradingPaused(),
+		}
+	}
+	return statuses
+}
+
+// haltKeys returns the mimir keys that can halt or pause the chain.
+func haltKeys(chain string) []string {
+	return []string{
+		"HALTCHAINGLOBAL",
+		"HALTTRADING",
+		"NODEPAUSECHAINGLOBAL",
+		fmt.Sprintf("HALT%sCHAIN", chain),
+		fmt.Sprintf("HALT%sTRADING", chain),
+		fmt.Sprintf("HALTSIGNING%s", chain),
+		fmt.Sprintf("SOLVENCYHALT%sCHAIN", chain),
+	}
+}
+
+// haltCauses lists the halt mimir keys in effect for chain with their source.
+func haltCauses(chain string, height int, mimir, admin map[string]int64) []string {
+	var causes []string
+	for _, key := range haltKeys(chain) {
+		value, ok := mimir[key]
+		if !ok || value <= 0 {
+			continue
+		}
+		var source string
+		switch {
+		case key == "NODEPAUSECHAINGLOBAL":
+			// node pauses hold until the given height
+			if int(value) <= height {
+				continue
+			}
+			source = "node pause"
+		case int(value) > height:
+			continue // scheduled for a later height
+		case strings.HasPrefix(key, "SOLVENCYHALT"):
+			source = "solvency auto-halt"
+		case admin[key] == value:
+			source = "admin"
+		default:
+			source = "node vote"
+		}
+		causes = append(causes, fmt.Sprintf("`%s` = `%d` (%s)", key, value, source))
+	}
+	return causes
+}
+
+func describeHaltStatus(s chainHaltStatus) string {
+	var parts []string
+	if s.Halted {
+		parts = append(parts, "halted")
+	}
+	if s.TradingPaused {
+		parts = append(parts, "chain trading paused")
+	}
+	if s.GlobalPaused {
+		parts = append(parts, "global trading paused")
+	}
+	return strings.Join(parts, ", ")
+}
+
+// insolvencyFindings returns the solvency findings for assets on chain.
+func insolvencyFindings(chain string, insolvencies []Insolvency) []string {
+	var findings []string
+	for _, insolvency := range insolvencies {
+		if strings.SplitN(insolvency.Asset, ".", 2)[0] != chain {
+			continue
+		}
+		findings = append(findings, fmt.Sprintf("> **Insolvent:** `%s` at %s (vault %s, %s)", insolvency.Asset, insolvency.Address, insolvency.Vault, insolvency.Diff))
+	}
+	return findings
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// Check
+////////////////////////////////////////////////////////////////////////////////
+
+func (chm *ChainHaltMonitor) Check() ([]notify.Alert, error) {
+	log.Info().Msg("Checking chain halts...")
+	cfg := config.Get()
+
+	client, height, err := common.PinLatestHeight(chm.client)
+	if err != nil {
+		return nil, err
+	}
+	addresses, err := client.GetInboundAddresses()
+	if err != nil {
+		return nil, err
+	}
+	mimir, err := client.GetMimir()
+	if err != nil {
+		return nil, err
+	}
+	admin, err := client.GetAdminMimir()
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := chainHaltStatuses(addresses)
+	chains := make([]string, 0, len(statuses))
+	for chain := range statuses {
+		chains = append(chains, chain)
+	}
+	sort.Strings(chains)
+
+	var alerts []notify.Alert
+	for _, chain := range chains {
+		status, prev := statuses[chain], chm.last[chain]
+		chm.last[chain] = status
+		if !chm.initialized || status == prev {
+			continue
+		}
+
+		if !status.any() {
+			msg := fmt.Sprintf("### Chain Unhalted: %s\n> Trading resumed (was %s)", chain, describeHaltStatus(prev))
+			alerts = append(alerts, notify.Alert{Webhooks: cfg.Webhooks.Activity, Message: msg, Height: height})
+			continue
+		}
+
+		msgs := []string{fmt.Sprintf("### Chain Halted: %s\n> **Status:** %s", chain, describeHaltStatus(status))}
+		causes := haltCauses(chain, height, mimir, admin)
+		if len(causes) == 0 {
+			msgs = append(msgs, "> **Cause:** unknown")
+		}
+		solvencyHalt := false
+		for _, cause := range causes {
+			msgs = append(msgs, "> **Cause:** "+cause)
+			solvencyHalt = solvencyHalt || strings.Contains(cause, "solvency auto-halt")
+		}
+		if solvencyHalt && chm.solvency != nil {
+			findings := insolvencyFindings(chain, chm.solvency.LastInsolvencies())
+			if len(findings) == 0 {
+				findings = []string{"> **Insolvent:** none reported by SolvencyMonitor"}
+			}
+			msgs = append(msgs, findings...)
+		}
+		alerts = append(alerts, notify.Alert{Webhooks: cfg.Webhooks.Activity, Message: strings.Join(msgs, "\n"), Height: height})
+	}
+	chm.initialized = true
+	return alerts, nil
+}
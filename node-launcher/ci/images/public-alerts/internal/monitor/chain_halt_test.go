@@ -0,0 +1,99 @@
+package monitor
+
+import (
+	"public-alerts/internal/common"
+	"strings"
+	"testing"
+
+	openapi "gitlab.com/thorchain/thornode/openapi/gen"
+)
+
+type fakeInsolvencySource []Insolvency
+
+func (f fakeInsolvencySource) LastInsolvencies() []Insolvency {
+	return f
+}
+
+func inboundAddress(chain string, halted, tradingPaused bool) openapi.InboundAddress {
+	return openapi.InboundAddress{Chain: &chain, Halted: halted, ChainTradingPaused: &tradingPaused}
+}
+
+func TestHaltCauses(t *testing.T) {
+	mimir := map[string]int64{
+		"HALTETHCHAIN":         90,
+		"SOLVENCYHALTETHCHAIN": 95,
+		"HALTETHTRADING":       200, // scheduled
+		"HALTSIGNINGETH":       1,
+		"NODEPAUSECHAINGLOBAL": 150,
+		"HALTBTCCHAIN":         1,
+	}
+	admin := map[string]int64{"HALTETHCHAIN": 90}
+
+	causes := haltCauses("ETH", 100, mimir, admin)
+	want := []string{
+		"`NODEPAUSECHAINGLOBAL` = `150` (node pause)",
+		"`HALTETHCHAIN` = `90` (admin)",
+		"`HALTSIGNINGETH` = `1` (node vote)",
+		"`SOLVENCYHALTETHCHAIN` = `95` (solvency auto-halt)",
+	}
+	if strings.Join(causes, "\n") != strings.Join(want, "\n") {
+		t.Errorf("unexpected causes:\n%s\nwant:\n%s", strings.Join(causes, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestChainHaltMonitorCheck(t *testing.T) {
+	client := common.NewFakeClient()
+	client.Height = 100
+	client.Inbound = []openapi.InboundAddress{inboundAddress("BTC", false, false), inboundAddress("ETH", false, false)}
+
+	solvency := fakeInsolvencySource{
+		{Asset: "ETH.USDC-0XA0B8", Address: "0xva...ault", Vault: "abcd", Diff: "-3.00%"},
+		{Asset: "BTC.BTC", Address: "bc1q...ault", Vault: "abcd", Diff: "-5.00%"},
+	}
+	chm := NewChainHaltMonitor(client, solvency)
+
+	if alerts, err := chm.Check(); err != nil || len(alerts) != 0 {
+		t.Fatalf("expected the first check to only record state, got %v (%v)", alerts, err)
+	}
+
+	// ETH is auto-halted by the solvency checker
+	client.Height = 110
+	client.Inbound = []openapi.InboundAddress{inboundAddress("BTC", false, false), inboundAddress("ETH", true, true)}
+	client.Mimir["SOLVENCYHALTETHCHAIN"] = 105
+
+	alerts, err := chm.Check()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(alerts) != 1 {
+		t.Fatalf("expected 1 alert, got %d", len(alerts))
+	}
+	for _, want := range []string{
+		"### Chain Halted: ETH",
+		"**Status:** halted, chain trading paused",
+		"**Cause:** `SOLVENCYHALTETHCHAIN` = `105` (solvency auto-halt)",
+		"**Insolvent:** `ETH.USDC-0XA0B8` at 0xva...ault (vault abcd, -3.00%)",
+	} {
+		if !strings.Contains(alerts[0].Message, want) {
+			t.Errorf("expected message to contain %q, got:\n%s", want, alerts[0].Message)
+		}
+	}
+	if strings.Contains(alerts[0].Message, "BTC.BTC") || alerts[0].Height != 110 {
+		t.Errorf("unexpected alert: %+v", alerts[0])
+	}
+
+	if alerts, _ = chm.Check(); len(alerts) != 0 {
+		t.Errorf("expected no repeated alerts, got %v", alerts)
+	}
+
+	// ETH resumes
+	client.Inbound = []openapi.InboundAddress{inboundAddress("BTC", false, false), inboundAddress("ETH", false, false)}
+	delete(client.Mimir, "SOLVENCYHALTETHCHAIN")
+	alerts, err = chm.Check()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(alerts) != 1 || !strings.Contains(alerts[0].Message, "### Chain Unhalted: ETH\n> Trading resumed (was halted, chain trading paused)") {
+		t.Errorf("expected unhalted alert, got %v", alerts)
+	}
+}
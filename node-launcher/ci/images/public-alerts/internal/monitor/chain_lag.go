@@ -5,6 +5,7 @@ import (
 	"public-alerts/internal/common"
 	"public-alerts/internal/config"
 	"public-alerts/internal/notify"
+	"sort"
 	"strings"
 	"time"
 
@@ -12,12 +13,18 @@ import (
 	openapi "gitlab.com/thorchain/thornode/openapi/gen"
 )
 
-var (
-	lastChainLag = make(map[string]int)
-	lastAlert    = time.Now()
-)
-
 type ChainLagMonitor struct {
+	client       common.ThornodeDataFetcher
+	lastChainLag map[string]int
+	lastAlert    time.Time
+}
+
+func NewChainLagMonitor(client common.ThornodeDataFetcher) *ChainLagMonitor {
+	return &ChainLagMonitor{
+		client:       client,
+		lastChainLag: make(map[string]int),
+		lastAlert:    time.Now(),
+	}
 }
 
 func (clm *ChainLagMonitor) Name() string {
@@ -55,9 +62,17 @@ func calculateChainLag(nodes []openapi.Node, maxChainLag map[string]int) ([]stri
 		activeNodes++
 	}
 
+	// iterate chains in a stable order so messages are deterministic
+	chains := make([]string, 0, len(chainHeights))
+	for chain := range chainHeights {
+		chains = append(chains, chain)
+	}
+	sort.Strings(chains)
+
 	var msgs []string
 	newLagCounts := make(map[string]int)
-	for chain, heights := range chainHeights {
+	for _, chain := range chains {
+		heights := chainHeights[chain]
 		maxLag, ok := maxChainLag[chain]
 		if !ok {
 			continue
@@ -95,7 +110,7 @@ func (clm *ChainLagMonitor) Check() ([]notify.Alert, error) {
 
 	log.Info().Msg("Checking Chain Lag...")
 	cfg := config.Get()
-	client, err := common.NewThornodeClient()
+	client, height, err := common.PinLatestHeight(clm.client)
 	if err != nil {
 		return nil, err
 	}
@@ -109,15 +124,15 @@ func (clm *ChainLagMonitor) Check() ([]notify.Alert, error) {
 
 	// Update global state
 	for chain, count := range newLagCounts {
-		lastChainLag[chain] = count
+		clm.lastChainLag[chain] = count
 	}
 
-	if len(msgs) > 0 && time.Since(lastAlert) > time.Hour {
+	if len(msgs) > 0 && time.Since(clm.lastAlert) > time.Hour {
 		msg := "```" + fmt.Sprintln(strings.Join(msgs, "\n")) + "```"
-		lastAlert = time.Now()
+		clm.lastAlert = time.Now()
 
 		alerts := []notify.Alert{
-			{Webhooks: cfg.Webhooks.Activity, Message: msg},
+			{Webhooks: cfg.Webhooks.Activity, Message: msg, Height: height},
 		}
 		return alerts, nil
 	}
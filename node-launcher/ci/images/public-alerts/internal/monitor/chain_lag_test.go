@@ -1,7 +1,10 @@
 package monitor
 
 import (
+	"public-alerts/internal/common"
+	"strings"
 	"testing"
+	"time"
 
 	openapi "gitlab.com/thorchain/thornode/openapi/gen"
 )
@@ -82,3 +85,38 @@ func TestCalculateChainLag(t *testing.T) {
 		})
 	}
 }
+
+func TestChainLagMonitorCheck(t *testing.T) {
+	client := common.NewFakeClient()
+	client.Nodes = []openapi.Node{
+		{Status: "Active", ObserveChains: []openapi.ChainHeight{{Chain: "BTC", Height: 100}}},
+		{Status: "Active", ObserveChains: []openapi.ChainHeight{{Chain: "BTC", Height: 90}}},
+		{Status: "Standby", ObserveChains: []openapi.ChainHeight{{Chain: "BTC", Height: 1}}},
+	}
+
+	clm := NewChainLagMonitor(client)
+
+	// alerts are suppressed within an hour of the previous alert
+	alerts, err := clm.Check()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(alerts) != 0 {
+		t.Errorf("expected no alerts within the cooldown, got %d", len(alerts))
+	}
+
+	clm.lastAlert = time.Now().Add(-2 * time.Hour)
+	alerts, err = clm.Check()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(alerts) != 1 {
+		t.Fatalf("expected 1 alert, got %d", len(alerts))
+	}
+	if !strings.Contains(alerts[0].Message, "[BTC] Lagging by over 3 blocks on 1 nodes.") {
+		t.Errorf("unexpected alert message: %s", alerts[0].Message)
+	}
+	if clm.lastChainLag["BTC"] != 1 {
+		t.Errorf("expected lag count for BTC to be recorded, got %d", clm.lastChainLag["BTC"])
+	}
+}
@@ -17,7 +17,8 @@ var detected = make(map[string]int)
 var mu sync.Mutex
 
 type ChainUpdateMonitor struct {
-	Daemons map[string]config.DaemonConfig
+	Daemons   map[string]config.DaemonConfig
+	githubAPI string
 }
 
 func (cup *ChainUpdateMonitor) Name() string {
@@ -28,19 +29,19 @@ func NewChainUpdateMonitor() *ChainUpdateMonitor {
 
 	daemons := config.Get().ChainUpdateMonitor.Daemons
 
-	return &ChainUpdateMonitor{daemons}
+	return &ChainUpdateMonitor{Daemons: daemons, githubAPI: config.Get().Endpoints.GithubAPI}
 }
 
 ////////////////////////////////////////////////////////////////////////////////
 // helpers
 ////////////////////////////////////////////////////////////////////////////////
 
-func fetchReleases(daemonInfo config.DaemonConfig) ([]struct {
+func fetchReleases(githubAPI string, daemonInfo config.DaemonConfig) ([]struct {
 	TagName string `json:"tag_name"`
 	HTMLURL string `json:"html_url"`
 }, error) {
 
-	url := fmt.Sprintf("https://api.github.com/repos/%s/releases", daemonInfo.Github)
+	url := fmt.Sprintf("%s/repos/%s/releases", githubAPI, daemonInfo.Github)
 
 	resp, err := http.Get(url)
 	if err != nil || resp.StatusCode != 200 {
@@ -89,11 +90,11 @@ func writeLatestTag(daemonInfo config.DaemonConfig) (notify.Alert, error) {
 // checkChainUpdates
 ////////////////////////////////////////////////////////////////////////////////
 
-func checkChainUpdates(daemonInfo config.DaemonConfig) ([]notify.Alert, error) {
+func checkChainUpdates(githubAPI string, daemonInfo config.DaemonConfig) ([]notify.Alert, error) {
 
 	var internalAlert []notify.Alert
 
-	daemonReleases, err := fetchReleases(daemonInfo)
+	daemonReleases, err := fetchReleases(githubAPI, daemonInfo)
 
 	if err != nil {
 		err_msg := fmt.Sprintf("Failed to decode response for %s: %v", daemonInfo.Name, err)
@@ -183,7 +184,7 @@ func (cup *ChainUpdateMonitor) Check() ([]notify.Alert, error) {
 			continue
 		}
 		if daemonInfo.Github != "" {
-			daemonAlert, err := checkChainUpdates(daemonInfo)
+			daemonAlert, err := checkChainUpdates(cup.githubAPI, daemonInfo)
 			if err != nil {
 				return daemonAlert, err
 			} else {
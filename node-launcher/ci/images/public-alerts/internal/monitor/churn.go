@@ -0,0 +1,207 @@
+package monitor
+
+import (
+	"fmt"
+	"public-alerts/internal/common"
+	"public-alerts/internal/config"
+	"public-alerts/internal/notify"
+	"sort"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+	openapi "gitlab.com/thorchain/thornode/openapi/gen"
+)
+
+// ChurnMonitor follows churns through the asgard vaults: it reports when a churn
+// starts (new retiring vaults), how the active set changed and when the retiring
+// vaults have finished migrating. Slow migrations are reported by the MigrationMonitor.
+type ChurnMonitor struct {
+	client      common.ThornodeDataFetcher
+	initialized bool
+	active      map[string]bool // active node addresses at the last check
+	churnStart  int             // height the current churn started, 0 when not churning
+}
+
+func NewChurnMonitor(client common.ThornodeDataFetcher) *ChurnMonitor {
+	return &ChurnMonitor{
+		client: client,
+		active: make(map[string]bool),
+	}
+}
+
+func (cm *ChurnMonitor) Name() string {
+	return "ChurnMonitor"
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// Helpers
+////////////////////////////////////////////////////////////////////////////////
+
+func activeSet(nodes []openapi.Node) map[string]bool {
+	active := make(map[string]bool)
+	for _, node := range nodes {
+		if node.Status == "Active" {
+			active[node.NodeAddress] = true
+		}
+	}
+	return active
+}
+
+func retiringVaults(vaults []openapi.Vault) []openapi.Vault {
+	var retiring []openapi.Vault
+	for _, vault := range vaults {
+		if vault.Status == "RetiringVault" {
+			retiring = append(retiring, vault)
+		}
+	}
+	return retiring
+}
+
+// churnStartHeight returns the height the retiring vaults started retiring at,
+// falling back to the current height when the vaults don't report it.
+func churnStartHeight(retiring []openapi.Vault, height int) int {
+	start := height
+	for _, vault := range retiring {
+		if since := int(vault.GetStatusSince()); since > 0 && since < start {
+			start = since
+		}
+	}
+	return start
+}
+
+// leaveReason describes why a node left the active set and whether it was forced out.
+func leaveReason(node *openapi.Node) (string, bool) {
+	if node == nil {
+		return "removed from node list", false
+	}
+	var reason string
+	forced := false
+	switch {
+	case node.ForcedToLeave:
+		reason, forced = "forced to leave", true
+	case node.Status == "Disabled":
+		reason, forced = "disabled", true
+	case node.RequestedToLeave:
+		reason = "requested to leave"
+	default:
+		reason = "churned out"
+	}
+	if jailReason := node.Jail.GetReason(); jailReason != "" {
+		reason += fmt.Sprintf(", jailed: %s", jailReason)
+	}
+	return reason, forced
+}
+
+// diffActiveSet returns a message describing the nodes that joined and left the
+// active set since previous, or an empty string when it did not change.
+func diffActiveSet(previous map[string]bool, nodes []openapi.Node) string {
+	byAddress := make(map[string]*openapi.Node, len(nodes))
+	for i := range nodes {
+		byAddress[nodes[i].NodeAddress] = &nodes[i]
+	}
+	current := activeSet(nodes)
+
+	var joined, left, forced []string
+	for address := range current {
+		if !previous[address] {
+			joined = append(joined, fmt.Sprintf("`%s`", common.ShortenAddress(address)))
+		}
+	}
+	for address := range previous {
+		if current[address] {
+			continue
+		}
+		reason, isForced := leaveReason(byAddress[address])
+		entry := fmt.Sprintf("`%s` (%s)", common.ShortenAddress(address), reason)
+		if isForced {
+			forced = append(forced, entry)
+		} else {
+			left = append(left, entry)
+		}
+	}
+	if len(joined) == 0 && len(left) == 0 && len(forced) == 0 {
+		return ""
+	}
+	sort.Strings(joined)
+	sort.Strings(left)
+	sort.Strings(forced)
+
+	msgs := []string{fmt.Sprintf("### Active Set Changed\n> **Active Nodes:** %d (%+d)", len(current), len(current)-len(previous))}
+	if len(joined) > 0 {
+		msgs = append(msgs, "> **Joined:** "+strings.Join(joined, ", "))
+	}
+	if len(left) > 0 {
+		msgs = append(msgs, "> **Left:** "+strings.Join(left, ", "))
+	}
+	if len(forced) > 0 {
+		msgs = append(msgs, "> **Forced Out:** "+strings.Join(forced, ", "))
+	}
+	return strings.Join(msgs, "\n")
+}
+
+// describeRetiring lists the retiring vaults with the number of coins left to migrate.
+func describeRetiring(retiring []openapi.Vault) []string {
+	var lines []string
+	for _, vault := range retiring {
+		remaining := 0
+		for _, coin := range vault.Coins {
+			if coin.Amount != "0" {
+				remaining++
+			}
+		}
+		lines = append(lines, fmt.Sprintf("> **Vault:** `%s` (%d assets remaining)", common.ShortenPubKey(vault.GetPubKey()), remaining))
+	}
+	return lines
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// Check
+////////////////////////////////////////////////////////////////////////////////
+
+func (cm *ChurnMonitor) Check() ([]notify.Alert, error) {
+	log.Info().Msg("Checking churn...")
+	cfg := config.Get()
+
+	client, height, err := common.PinLatestHeight(cm.client)
+	if err != nil {
+		return nil, err
+	}
+	nodes, err := client.GetNodes()
+	if err != nil {
+		return nil, err
+	}
+	vaults, err := client.GetVaults()
+	if err != nil {
+		return nil, err
+	}
+	retiring := retiringVaults(vaults)
+
+	var msgs []string
+	if !cm.initialized {
+		// the first check only records state, a churn already in progress is still timed
+		// for its completion
+		cm.initialized = true
+		if len(retiring) > 0 {
+			cm.churnStart = churnStartHeight(retiring, height)
+		}
+	} else {
+		if msg := diffActiveSet(cm.active, nodes); msg != "" {
+			msgs = append(msgs, msg)
+		}
+		switch {
+		case len(retiring) > 0 && cm.churnStart == 0:
+			cm.churnStart = churnStartHeight(retiring, height)
+			msgs = append(msgs, strings.Join(append([]string{"### Churn Started"}, describeRetiring(retiring)...), "\n"))
+		case len(retiring) == 0 && cm.churnStart > 0:
+			msgs = append(msgs, fmt.Sprintf("### Churn Complete\n> **Migration:** %d blocks", height-cm.churnStart))
+			cm.churnStart = 0
+		}
+	}
+	cm.active = activeSet(nodes)
+
+	var alerts []notify.Alert
+	for _, msg := range msgs {
+		alerts = append(alerts, notify.Alert{Webhooks: cfg.Webhooks.Activity, Message: msg, Height: height})
+	}
+	return alerts, nil
+}
@@ -0,0 +1,97 @@
+package monitor
+
+import (
+	"public-alerts/internal/common"
+	"strings"
+	"testing"
+
+	openapi "gitlab.com/thorchain/thornode/openapi/gen"
+)
+
+func testVault(pubKey, status string, since int64, amount string) openapi.Vault {
+	return openapi.Vault{
+		PubKey:      &pubKey,
+		Status:      status,
+		StatusSince: &since,
+		Coins:       []openapi.Coin{{Asset: "BTC.BTC", Amount: amount}},
+	}
+}
+
+func TestDiffActiveSet(t *testing.T) {
+	jailReason := "failed to sign"
+	previous := map[string]bool{"thor1nodeaaaa": true, "thor1nodebbbb": true, "thor1nodecccc": true, "thor1nodedddd": true}
+	nodes := []openapi.Node{
+		{NodeAddress: "thor1nodeaaaa", Status: "Active"},
+		{NodeAddress: "thor1nodebbbb", Status: "Standby", RequestedToLeave: true},
+		{NodeAddress: "thor1nodecccc", Status: "Disabled", ForcedToLeave: true, Jail: openapi.NodeJail{Reason: &jailReason}},
+		{NodeAddress: "thor1nodeeeee", Status: "Active"},
+	}
+
+	msg := diffActiveSet(previous, nodes)
+	for _, want := range []string{
+		"**Active Nodes:** 2 (-2)",
+		"**Joined:** `thor...eeee`",
+		"**Left:** `thor...bbbb` (requested to leave), `thor...dddd` (removed from node list)",
+		"**Forced Out:** `thor...cccc` (forced to leave, jailed: failed to sign)",
+	} {
+		if !strings.Contains(msg, want) {
+			t.Errorf("expected message to contain %q, got:\n%s", want, msg)
+		}
+	}
+
+	if msg := diffActiveSet(map[string]bool{"thor1nodeaaaa": true}, nodes[:1]); msg != "" {
+		t.Errorf("expected no change, got %q", msg)
+	}
+}
+
+func TestChurnMonitorCheck(t *testing.T) {
+	client := common.NewFakeClient()
+	client.Height = 1000
+	client.Nodes = []openapi.Node{{NodeAddress: "thor1old", Status: "Active"}, {NodeAddress: "thor1new", Status: "Ready"}}
+	client.Vaults = []openapi.Vault{testVault("pubold", "ActiveVault", 1, "100")}
+
+	cm := NewChurnMonitor(client)
+	check := func() []string {
+		t.Helper()
+		alerts, err := cm.Check()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		var msgs []string
+		for _, alert := range alerts {
+			if alert.Height != client.Height {
+				t.Errorf("expected alert at height %d, got %d", client.Height, alert.Height)
+			}
+			msgs = append(msgs, alert.Message)
+		}
+		return msgs
+	}
+
+	if msgs := check(); len(msgs) != 0 {
+		t.Fatalf("expected the first check to only record state, got %v", msgs)
+	}
+
+	// churn starts: the new node joins and the old vault retires
+	client.Height = 1010
+	client.Nodes = []openapi.Node{{NodeAddress: "thor1old", Status: "Active"}, {NodeAddress: "thor1new", Status: "Active"}}
+	client.Vaults = []openapi.Vault{testVault("pubold", "RetiringVault", 1005, "100"), testVault("pubnew", "ActiveVault", 1005, "0")}
+	msgs := check()
+	if len(msgs) != 2 || !strings.Contains(msgs[0], "Active Set Changed") || !strings.Contains(msgs[1], "Churn Started") {
+		t.Fatalf("expected active set change and churn start, got %v", msgs)
+	}
+	if msgs := check(); len(msgs) != 0 {
+		t.Errorf("expected no repeated alerts, got %v", msgs)
+	}
+
+	// a slow migration is left to the migration monitor
+	client.Height = 10000
+	if msgs := check(); len(msgs) != 0 {
+		t.Errorf("expected no alert for a slow migration, got %v", msgs)
+	}
+
+	// retiring vault is gone
+	client.Vaults = client.Vaults[1:]
+	if msgs := check(); len(msgs) != 1 || !strings.Contains(msgs[0], "Churn Complete") || !strings.Contains(msgs[0], "8995 blocks") {
+		t.Fatalf("expected churn complete alert, got %v", msgs)
+	}
+}
@@ -0,0 +1,205 @@
+package monitor
+
+import (
+	"fmt"
+	"public-alerts/internal/common"
+	"public-alerts/internal/config"
+	"public-alerts/internal/notify"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+	openapi "gitlab.com/thorchain/thornode/openapi/gen"
+)
+
+// EconomicSecurityMonitor compares the effective bond of the active set, the bond
+// of the bottom 2/3 of active nodes that could collude to sign, with the RUNE
+// pooled in the available pools and with the non-RUNE value held in the asgard
+// vaults. It alerts when either ratio falls below its threshold and again when it
+// recovers, and when a vault holds more value than its members have bonded.
+type EconomicSecurityMonitor struct {
+	client  common.ThornodeDataFetcher
+	tripped map[string]bool // conditions already alerted
+}
+
+func NewEconomicSecurityMonitor(client common.ThornodeDataFetcher) *EconomicSecurityMonitor {
+	return &EconomicSecurityMonitor{
+		client:  client,
+		tripped: make(map[string]bool),
+	}
+}
+
+func (em *EconomicSecurityMonitor) Name() string {
+	return "EconomicSecurityMonitor"
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// Helpers
+////////////////////////////////////////////////////////////////////////////////
+
+// securityRatios are the ratio conditions in alert order, with the label of their
+// denominator and the message sent when they recover.
+var securityRatios = []struct {
+	key, label, recovery string
+}{
+	{"pooled", "Pooled RUNE", "### Economic Security Restored: Pooled RUNE"},
+	{"vaults", "Vault Value", "### Economic Security Restored: Vault Value"},
+}
+
+func formatRune(amount float64) string {
+	return fmt.Sprintf("%.0f RUNE", amount/1e8)
+}
+
+// effectiveBond sums the bonds of the bottom 2/3 of active nodes, the smallest
+// bond that controls enough signers to move vault funds.
+func effectiveBond(nodes []openapi.Node) float64 {
+	var bonds []float64
+	for _, node := range nodes {
+		if node.Status != "Active" {
+			continue
+		}
+		bond, _ := strconv.ParseFloat(node.TotalBond, 64)
+		bonds = append(bonds, bond)
+	}
+	sort.Float64s(bonds)
+	total := 0.0
+	for _, bond := range bonds[:len(bonds)*2/3] {
+		total += bond
+	}
+	return total
+}
+
+// pooledRune sums the RUNE depth of the available pools.
+func pooledRune(pools []openapi.Pool) float64 {
+	total := 0.0
+	for _, pool := range pools {
+		if pool.Status != "Available" {
+			continue
+		}
+		depth, _ := strconv.ParseFloat(pool.BalanceRune, 64)
+		total += depth
+	}
+	return total
+}
+
+// vaultValueRune returns the RUNE value of the non-RUNE coins of a vault.
+func vaultValueRune(vault openapi.Vault, runePerAsset map[string]float64) float64 {
+	total := 0.0
+	for asset, amount := range vaultBalances(vault) {
+		if asset != common.RuneAsset {
+			total += amount * runePerAsset[asset]
+		}
+	}
+	return total
+}
+
+// memberBond sums the bonds of the nodes signing for a vault.
+func memberBond(vault openapi.Vault, bonds map[string]float64) float64 {
+	total := 0.0
+	for _, member := range vault.Membership {
+		total += bonds[member]
+	}
+	return total
+}
+
+// nodeBonds returns the bond of each node by its secp256k1 pubkey.
+func nodeBonds(nodes []openapi.Node) map[string]float64 {
+	bonds := make(map[string]float64)
+	for _, node := range nodes {
+		if node.PubKeySet.Secp256k1 == nil {
+			continue
+		}
+		bond, _ := strconv.ParseFloat(node.TotalBond, 64)
+		bonds[*node.PubKeySet.Secp256k1] = bond
+	}
+	return bonds
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// Check
+////////////////////////////////////////////////////////////////////////////////
+
+func (em *EconomicSecurityMonitor) Check() ([]notify.Alert, error) {
+	log.Info().Msg("Checking economic security...")
+	cfg := config.Get()
+	ecfg := cfg.EconomicSecurityMonitor
+
+	client, height, err := common.PinLatestHeight(em.client)
+	if err != nil {
+		return nil, err
+	}
+	nodes, err := client.GetNodes()
+	if err != nil {
+		return nil, err
+	}
+	pools, err := client.GetPools()
+	if err != nil {
+		return nil, err
+	}
+	vaults, err := client.GetVaults()
+	if err != nil {
+		return nil, err
+	}
+
+	bond := effectiveBond(nodes)
+	runePerAsset := common.PoolRunePrices(pools)
+	bonds := nodeBonds(nodes)
+
+	totals := map[string]float64{"pooled": pooledRune(pools)}
+	var undersecured []openapi.Vault
+	for _, vault := range vaults {
+		if vault.Status != "ActiveVault" && vault.Status != "RetiringVault" {
+			continue
+		}
+		value := vaultValueRune(vault, runePerAsset)
+		totals["vaults"] += value
+		if len(vault.Membership) > 0 && value > memberBond(vault, bonds) {
+			undersecured = append(undersecured, vault)
+		}
+	}
+	minimums := map[string]float64{"pooled": ecfg.MinBondToPooled, "vaults": ecfg.MinBondToVaults}
+
+	var alerts []notify.Alert
+	for _, r := range securityRatios {
+		if totals[r.key] <= 0 {
+			continue
+		}
+		ratio := bond / totals[r.key]
+		below := ratio < minimums[r.key]
+		var title string
+		switch {
+		case below && !em.tripped[r.key]:
+			em.tripped[r.key] = true
+			title = "### Economic Security Below Threshold: " + r.label
+		case !below && em.tripped[r.key]:
+			delete(em.tripped, r.key)
+			title = r.recovery
+		default:
+			continue
+		}
+		msg := fmt.Sprintf("%s\n> **Effective Bond / %s:** %.2f (minimum %.2f)\n> **Effective Bond:** %s\n> **%s:** %s",
+			title, r.label, ratio, minimums[r.key], formatRune(bond), r.label, formatRune(totals[r.key]))
+		alerts = append(alerts, notify.Alert{Webhooks: cfg.Webhooks.Security, Message: msg, Height: height})
+	}
+
+	current := make(map[string]bool, len(undersecured))
+	for _, vault := range undersecured {
+		key := "vault/" + vault.GetPubKey()
+		current[key] = true
+		if em.tripped[key] {
+			continue
+		}
+		em.tripped[key] = true
+		msg := fmt.Sprintf("### Vault Undersecured: `%s`\n> **Vault Value:** %s\n> **Member Bond:** %s (%d members)",
+			common.ShortenPubKey(vault.GetPubKey()), formatRune(vaultValueRune(vault, runePerAsset)),
+			formatRune(memberBond(vault, bonds)), len(vault.Membership))
+		alerts = append(alerts, notify.Alert{Webhooks: cfg.Webhooks.Security, Message: msg, Height: height})
+	}
+	for key := range em.tripped {
+		if strings.HasPrefix(key, "vault/") && !current[key] {
+			delete(em.tripped, key)
+		}
+	}
+	return alerts, nil
+}
@@ -0,0 +1,136 @@
+package monitor
+
+import (
+	"errors"
+	"public-alerts/internal/common"
+	"strings"
+	"testing"
+
+	openapi "gitlab.com/thorchain/thornode/openapi/gen"
+)
+
+func bondedNode(pubKey, status, bond string) openapi.Node {
+	return openapi.Node{NodeAddress: "thor" + pubKey, Status: status, TotalBond: bond, PubKeySet: openapi.NodePubKeySet{Secp256k1: &pubKey}}
+}
+
+func TestEffectiveBond(t *testing.T) {
+	tests := []struct {
+		name     string
+		nodes    []openapi.Node
+		expected float64
+	}{
+		{"no nodes", nil, 0},
+		{"bottom two thirds", []openapi.Node{
+			bondedNode("pk1", "Active", "300"),
+			bondedNode("pk2", "Active", "100"),
+			bondedNode("pk3", "Active", "200"),
+		}, 300},
+		{"standby nodes ignored", []openapi.Node{
+			bondedNode("pk1", "Active", "300"),
+			bondedNode("pk2", "Active", "100"),
+			bondedNode("pk3", "Active", "200"),
+			bondedNode("pk4", "Standby", "1"),
+		}, 300},
+		{"rounds down", []openapi.Node{
+			bondedNode("pk1", "Active", "100"),
+			bondedNode("pk2", "Active", "200"),
+			bondedNode("pk3", "Active", "300"),
+			bondedNode("pk4", "Active", "400"),
+		}, 300},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if bond := effectiveBond(tt.nodes); bond != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, bond)
+			}
+		})
+	}
+}
+
+func TestEconomicSecurityMonitorCheck(t *testing.T) {
+	vault := func(btc string) openapi.Vault {
+		pubKey := "thorpubVAULT"
+		return openapi.Vault{PubKey: &pubKey, Status: "ActiveVault", Membership: []string{"pk1", "pk2", "pk3"},
+			Coins: []openapi.Coin{{Asset: "BTC.BTC", Amount: btc}}}
+	}
+
+	client := common.NewFakeClient()
+	client.Height = 1000
+	client.Nodes = []openapi.Node{
+		bondedNode("pk1", "Active", "10000000000"),
+		bondedNode("pk2", "Active", "20000000000"),
+		bondedNode("pk3", "Active", "30000000000"),
+	}
+	client.Pools = []openapi.Pool{depthPool("BTC.BTC", "100000000", "20000000000")}
+	client.Vaults = []openapi.Vault{vault("100000000")}
+
+	em := NewEconomicSecurityMonitor(client)
+	alerts, err := em.Check()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(alerts) != 0 {
+		t.Fatalf("expected no alerts while secured, got %v", alerts)
+	}
+
+	// BTC doubles in RUNE terms and the vault receives more BTC
+	client.Pools = []openapi.Pool{depthPool("BTC.BTC", "100000000", "40000000000")}
+	client.Vaults = []openapi.Vault{vault("200000000")}
+	alerts, err = em.Check()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []string{
+		strings.Join([]string{
+			"### Economic Security Below Threshold: Pooled RUNE",
+			"> **Effective Bond / Pooled RUNE:** 0.75 (minimum 1.00)",
+			"> **Effective Bond:** 300 RUNE",
+			"> **Pooled RUNE:** 400 RUNE",
+		}, "\n"),
+		strings.Join([]string{
+			"### Economic Security Below Threshold: Vault Value",
+			"> **Effective Bond / Vault Value:** 0.38 (minimum 1.00)",
+			"> **Effective Bond:** 300 RUNE",
+			"> **Vault Value:** 800 RUNE",
+		}, "\n"),
+		strings.Join([]string{
+			"### Vault Undersecured: `AULT`",
+			"> **Vault Value:** 800 RUNE",
+			"> **Member Bond:** 600 RUNE (3 members)",
+		}, "\n"),
+	}
+	if len(alerts) != len(expected) {
+		t.Fatalf("expected %d alerts, got %v", len(expected), alerts)
+	}
+	for i, alert := range alerts {
+		if alert.Message != expected[i] {
+			t.Errorf("expected %q, got %q", expected[i], alert.Message)
+		}
+		if alert.Height != 1000 {
+			t.Errorf("expected height 1000, got %d", alert.Height)
+		}
+	}
+
+	// ongoing conditions are not repeated
+	if alerts, err = em.Check(); err != nil || len(alerts) != 0 {
+		t.Fatalf("expected no repeated alerts, got %v, %v", alerts, err)
+	}
+
+	// back to the original state both ratios recover
+	client.Pools = []openapi.Pool{depthPool("BTC.BTC", "100000000", "20000000000")}
+	client.Vaults = []openapi.Vault{vault("100000000")}
+	alerts, err = em.Check()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(alerts) != 2 ||
+		!strings.HasPrefix(alerts[0].Message, "### Economic Security Restored: Pooled RUNE") ||
+		!strings.HasPrefix(alerts[1].Message, "### Economic Security Restored: Vault Value") {
+		t.Fatalf("expected two recoveries, got %v", alerts)
+	}
+
+	client.Err = errors.New("unavailable")
+	if _, err := em.Check(); err == nil {
+		t.Error("expected error from client")
+	}
+}
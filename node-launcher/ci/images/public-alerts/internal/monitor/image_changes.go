@@ -1,9 +1,8 @@
 package monitor
 
 import (
-	"encoding/json"
 	"fmt"
-	"net/http"
+	"public-alerts/internal/common"
 	"public-alerts/internal/config"
 	"public-alerts/internal/notify"
 	"regexp"
@@ -12,22 +11,16 @@ import (
 )
 
 type ImageChangeMonitor struct {
+	client common.NineRealmsDataFetcher
 }
 
 func (img *ImageChangeMonitor) Name() string {
 	return "ImageChangeMonitor"
 }
 
-func NewImageChangeMonitor() *ImageChangeMonitor {
+func NewImageChangeMonitor(client common.NineRealmsDataFetcher) *ImageChangeMonitor {
 
-	return &ImageChangeMonitor{}
-}
-
-type Image struct {
-	Repo         string `json:"repo"`
-	Tag          string `json:"tag"`
-	Hash         string `json:"hash"`
-	PreviousHash string `json:"previous_hash"`
+	return &ImageChangeMonitor{client: client}
 }
 
 // keep track of images that have been observed
@@ -36,36 +29,11 @@ var (
 	IMAGE_FILTER = regexp.MustCompile(`^thorchain/((devops/node-launcher.*)|(thornode:(chaosnet-multichain|mainnet)-\d+\.\d+\.\d+)|(midgard:\d+\.\d+\.\d+))$`)
 )
 
-// //////////////////////////////////////////////////////////////////////////////
-// helpers
-// //////////////////////////////////////////////////////////////////////////////
-
-func FetchImages() ([]Image, error) {
-	//TODO - switch to new non-9R API endpoint, when available
-	response, err := http.Get(fmt.Sprintf("%s/thorchain/security/images", config.Get().Endpoints.NineRealmsAPI))
-	if err != nil {
-		return nil, err
-	}
-	defer response.Body.Close()
-
-	if response.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("failed to fetch images, status code: %d", response.StatusCode)
-	}
-
-	var images []Image
-	decoder := json.NewDecoder(response.Body)
-	if err := decoder.Decode(&images); err != nil {
-		return nil, err
-	}
-
-	return images, nil
-}
-
 ////////////////////////////////////////////////////////////////////////////////
 // checkImageChanges
 ////////////////////////////////////////////////////////////////////////////////
 
-func checkImageChanges(fetchFunc func() ([]Image, error)) ([]notify.Alert, error) {
+func checkImageChanges(fetchFunc func() ([]common.Image, error)) ([]notify.Alert, error) {
 
 	// get images
 	images, err := fetchFunc()
@@ -145,7 +113,7 @@ func (img *ImageChangeMonitor) Check() ([]notify.Alert, error) {
 	log.Info().Msg("Checking for image changes...")
 	log.Debug().Msgf("Seen images: %v", seen)
 
-	alerts, err := checkImageChanges(FetchImages)
+	alerts, err := checkImageChanges(img.client.GetImages)
 	if err != nil {
 		err_msg := fmt.Sprintf("Failed to check for image changes: %v", err)
 		return []notify.Alert{{Webhooks: config.Get().Webhooks.Activity, Message: err_msg}}, err
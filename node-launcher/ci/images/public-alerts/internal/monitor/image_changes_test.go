@@ -1,6 +1,7 @@
 package monitor
 
 import (
+	"public-alerts/internal/common"
 	"testing"
 )
 
@@ -27,8 +28,8 @@ func TestImageFilterRegex(t *testing.T) {
 }
 
 func TestCheckImageChanges(t *testing.T) {
-	mockFetchImages := func() ([]Image, error) {
-		return []Image{
+	mockFetchImages := func() ([]common.Image, error) {
+		return []common.Image{
 			{Repo: "thorchain/devops/node-launcher", Tag: "test", Hash: "hash1"},
 			{Repo: "thorchain/thornode", Tag: "chaosnet-multichain-1.2.3", Hash: "hash2"},
 			{Repo: "thorchain/midgard", Tag: "1.2.3", Hash: "hash3"},
@@ -45,8 +46,8 @@ func TestCheckImageChanges(t *testing.T) {
 	}
 
 	// Modify the image hash to simulate a change
-	mockFetchImages = func() ([]Image, error) {
-		return []Image{
+	mockFetchImages = func() ([]common.Image, error) {
+		return []common.Image{
 			{Repo: "thorchain/devops/node-launcher", Tag: "test", Hash: "hash1-modified"},
 			{Repo: "thorchain/thornode", Tag: "chaosnet-multichain-1.2.3", Hash: "hash2"},
 			{Repo: "thorchain/midgard", Tag: "1.2.3", Hash: "hash3"},
@@ -0,0 +1,188 @@
+package monitor
+
+import (
+	"fmt"
+	"public-alerts/internal/common"
+	"public-alerts/internal/config"
+	"public-alerts/internal/notify"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+	openapi "gitlab.com/thorchain/thornode/openapi/gen"
+)
+
+// minFeeSamples is the number of samples needed before fee spikes are rated.
+const minFeeSamples = 5
+
+// InboundMonitor snapshots the published inbound addresses. Any change of an EVM
+// router contract is a security alert, as is an inbound address that does not
+// belong to an active or retiring vault. Inbound addresses move to the new vaults
+// during a churn, so changes outside a churn are reported as activity. Gas rate,
+// outbound fee and dust threshold spikes above a multiple of their trailing
+// average are reported as activity as well.
+type InboundMonitor struct {
+	client      common.ThornodeDataFetcher
+	initialized bool
+	last        map[string]openapi.InboundAddress // by chain
+	history     map[string][]float64              // trailing "<chain>/<field>" fee samples
+	tripped     map[string]bool                   // fee spikes already alerted
+}
+
+func NewInboundMonitor(client common.ThornodeDataFetcher) *InboundMonitor {
+	return &InboundMonitor{
+		client:  client,
+		last:    make(map[string]openapi.InboundAddress),
+		history: make(map[string][]float64),
+		tripped: make(map[string]bool),
+	}
+}
+
+func (im *InboundMonitor) Name() string {
+	return "InboundMonitor"
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// Helpers
+////////////////////////////////////////////////////////////////////////////////
+
+// vaultAddresses returns the vault pubkey owning each "<chain>/<address>" of the
+// active and retiring asgard vaults.
+func vaultAddresses(vaults []openapi.Vault) map[string]string {
+	owners := make(map[string]string)
+	for _, vault := range vaults {
+		if vault.Status != "ActiveVault" && vault.Status != "RetiringVault" {
+			continue
+		}
+		for _, address := range vault.Addresses {
+			owners[address.Chain+"/"+address.Address] = vault.GetPubKey()
+		}
+	}
+	return owners
+}
+
+// inboundChanges returns the security and activity messages for router and
+// address changes of an inbound address since prev.
+func inboundChanges(prev, inbound openapi.InboundAddress, churning bool, owners map[string]string) (security, activity []string) {
+	chain := inbound.GetChain()
+	if prev.GetRouter() != inbound.GetRouter() {
+		security = append(security, fmt.Sprintf("### Router Changed: %s\n> **Router:** `%s` → `%s`", chain, prev.GetRouter(), inbound.GetRouter()))
+	}
+	if prev.GetAddress() == inbound.GetAddress() {
+		return security, activity
+	}
+	msg := fmt.Sprintf("> **Address:** `%s` → `%s`", prev.GetAddress(), inbound.GetAddress())
+	owner, known := owners[chain+"/"+inbound.GetAddress()]
+	switch {
+	case !known || owner != inbound.GetPubKey():
+		// even during a churn the new address must belong to a vault
+		security = append(security, fmt.Sprintf("### Unknown Inbound Address: %s\n%s\n> **Vault:** no active vault owns the new address", chain, msg))
+	case !churning:
+		activity = append(activity, fmt.Sprintf("### Inbound Address Changed: %s\n%s\n> **Vault:** `%s` (outside a churn)", chain, msg, common.ShortenPubKey(owner)))
+	}
+	return security, activity
+}
+
+// inboundFees returns the fee rates of an inbound address keyed by field.
+func inboundFees(inbound openapi.InboundAddress) map[string]float64 {
+	fees := make(map[string]float64)
+	for field, value := range map[string]*string{
+		"gas_rate":       inbound.GasRate,
+		"outbound_fee":   inbound.OutboundFee,
+		"dust_threshold": inbound.DustThreshold,
+	} {
+		if value == nil {
+			continue
+		}
+		if f, err := strconv.ParseFloat(*value, 64); err == nil {
+			fees[field] = f
+		}
+	}
+	return fees
+}
+
+// feeSpike returns the trailing average of samples and whether value exceeds it
+// by more than multiple. Too few samples are never a spike.
+func feeSpike(samples []float64, value, multiple float64) (float64, bool) {
+	if len(samples) < minFeeSamples {
+		return 0, false
+	}
+	total := 0.0
+	for _, s := range samples {
+		total += s
+	}
+	avg := total / float64(len(samples))
+	return avg, avg > 0 && value > avg*multiple
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// Check
+////////////////////////////////////////////////////////////////////////////////
+
+func (im *InboundMonitor) Check() ([]notify.Alert, error) {
+	log.Info().Msg("Checking inbound addresses...")
+	cfg := config.Get()
+
+	client, height, err := common.PinLatestHeight(im.client)
+	if err != nil {
+		return nil, err
+	}
+	addresses, err := client.GetInboundAddresses()
+	if err != nil {
+		return nil, err
+	}
+	vaults, err := client.GetVaults()
+	if err != nil {
+		return nil, err
+	}
+	churning := len(retiringVaults(vaults)) > 0
+	owners := vaultAddresses(vaults)
+	sort.Slice(addresses, func(i, j int) bool { return addresses[i].GetChain() < addresses[j].GetChain() })
+
+	var alerts []notify.Alert
+	var spikes []string
+	for _, inbound := range addresses {
+		chain := inbound.GetChain()
+		if prev, ok := im.last[chain]; ok && im.initialized {
+			security, activity := inboundChanges(prev, inbound, churning, owners)
+			for _, msg := range security {
+				alerts = append(alerts, notify.Alert{Webhooks: cfg.Webhooks.Security, Message: msg, Height: height})
+			}
+			for _, msg := range activity {
+				alerts = append(alerts, notify.Alert{Webhooks: cfg.Webhooks.Activity, Message: msg, Height: height})
+			}
+		}
+		im.last[chain] = inbound
+
+		fees := inboundFees(inbound)
+		fields := make([]string, 0, len(fees))
+		for field := range fees {
+			fields = append(fields, field)
+		}
+		sort.Strings(fields)
+		for _, field := range fields {
+			key, value := chain+"/"+field, fees[field]
+			avg, spiking := feeSpike(im.history[key], value, cfg.InboundMonitor.SpikeMultiple)
+			switch {
+			case spiking && !im.tripped[key]:
+				im.tripped[key] = true
+				spikes = append(spikes, fmt.Sprintf("> **%s `%s`:** %.0f, %.1fx the trailing average %.0f", chain, field, value, value/avg, avg))
+			case !spiking:
+				delete(im.tripped, key)
+			}
+			samples := append(im.history[key], value)
+			if len(samples) > cfg.InboundMonitor.TrailingSamples {
+				samples = samples[len(samples)-cfg.InboundMonitor.TrailingSamples:]
+			}
+			im.history[key] = samples
+		}
+	}
+	im.initialized = true
+
+	if len(spikes) > 0 {
+		msg := "### Inbound Fee Spike\n" + strings.Join(spikes, "\n")
+		alerts = append(alerts, notify.Alert{Webhooks: cfg.Webhooks.Activity, Message: msg, Height: height})
+	}
+	return alerts, nil
+}
@@ -0,0 +1,118 @@
+package monitor
+
+import (
+	"public-alerts/internal/common"
+	"strings"
+	"testing"
+
+	openapi "gitlab.com/thorchain/thornode/openapi/gen"
+)
+
+func inboundVault(chain, pubKey, address, router, gasRate string) openapi.InboundAddress {
+	return openapi.InboundAddress{Chain: &chain, PubKey: &pubKey, Address: &address, Router: &router, GasRate: &gasRate}
+}
+
+func asgard(pubKey, status string, addresses ...openapi.VaultAddress) openapi.Vault {
+	return openapi.Vault{PubKey: &pubKey, Status: status, Addresses: addresses}
+}
+
+func TestInboundChanges(t *testing.T) {
+	owners := map[string]string{"ETH/0xvault1": "thorpub1", "ETH/0xvault2": "thorpub2"}
+	prev := inboundVault("ETH", "thorpub1", "0xvault1", "0xrouter", "10")
+
+	tests := []struct {
+		name               string
+		inbound            openapi.InboundAddress
+		churning           bool
+		security, activity []string
+	}{
+		{
+			name:    "unchanged",
+			inbound: prev,
+		},
+		{
+			name:     "router changed",
+			inbound:  inboundVault("ETH", "thorpub1", "0xvault1", "0xevil", "10"),
+			security: []string{"### Router Changed: ETH"},
+		},
+		{
+			name:     "rotated to another vault",
+			inbound:  inboundVault("ETH", "thorpub2", "0xvault2", "0xrouter", "10"),
+			activity: []string{"### Inbound Address Changed: ETH"},
+		},
+		{
+			name:     "rotated during churn",
+			inbound:  inboundVault("ETH", "thorpub2", "0xvault2", "0xrouter", "10"),
+			churning: true,
+		},
+		{
+			name:     "unknown address during churn",
+			inbound:  inboundVault("ETH", "thorpub2", "0xevil", "0xrouter", "10"),
+			churning: true,
+			security: []string{"### Unknown Inbound Address: ETH"},
+		},
+	}
+	titles := func(msgs []string) string {
+		var t []string
+		for _, msg := range msgs {
+			t = append(t, strings.SplitN(msg, "\n", 2)[0])
+		}
+		return strings.Join(t, "|")
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			security, activity := inboundChanges(prev, tt.inbound, tt.churning, owners)
+			if titles(security) != strings.Join(tt.security, "|") || titles(activity) != strings.Join(tt.activity, "|") {
+				t.Errorf("expected %v and %v, got %v and %v", tt.security, tt.activity, security, activity)
+			}
+		})
+	}
+}
+
+func TestFeeSpike(t *testing.T) {
+	if _, spiking := feeSpike([]float64{10, 10}, 100, 3); spiking {
+		t.Error("expected too few samples not to spike")
+	}
+	samples := []float64{10, 12, 8, 10, 10}
+	if avg, spiking := feeSpike(samples, 31, 3); !spiking || avg != 10 {
+		t.Errorf("expected a spike over average 10, got %v, %v", avg, spiking)
+	}
+	if _, spiking := feeSpike(samples, 30, 3); spiking {
+		t.Error("expected 3x the average not to spike")
+	}
+}
+
+func TestInboundMonitorCheck(t *testing.T) {
+	client := common.NewFakeClient()
+	client.Height = 100
+	client.Vaults = []openapi.Vault{asgard("thorpub1", "ActiveVault", openapi.VaultAddress{Chain: "ETH", Address: "0xvault1"})}
+	client.Inbound = []openapi.InboundAddress{inboundVault("ETH", "thorpub1", "0xvault1", "0xrouter", "10")}
+
+	im := NewInboundMonitor(client)
+	for i := 0; i < minFeeSamples; i++ {
+		alerts, err := im.Check()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(alerts) != 0 {
+			t.Fatalf("expected no alerts for unchanged addresses, got %v", alerts)
+		}
+	}
+
+	// the router is swapped while gas spikes
+	client.Inbound = []openapi.InboundAddress{inboundVault("ETH", "thorpub1", "0xvault1", "0xevil", "50")}
+	alerts, err := im.Check()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(alerts) != 2 {
+		t.Fatalf("expected router and fee spike alerts, got %v", alerts)
+	}
+	if !strings.HasPrefix(alerts[0].Message, "### Router Changed: ETH\n> **Router:** `0xrouter` → `0xevil`") {
+		t.Errorf("unexpected router alert %q", alerts[0].Message)
+	}
+	expected := "### Inbound Fee Spike\n> **ETH `gas_rate`:** 50, 5.0x the trailing average 10"
+	if alerts[1].Message != expected {
+		t.Errorf("expected %q, got %q", expected, alerts[1].Message)
+	}
+}
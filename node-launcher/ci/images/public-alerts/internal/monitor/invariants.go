@@ -29,12 +29,7 @@ func (ldf *liveDataFetcher) FetchInvariantData(invariant string) (*openapi.Invar
 	return ldf.client.GetInvariant(invariant)
 }
 
-func NewLiveDataFetcher() *liveDataFetcher {
-	client, err := common.NewThornodeClient()
-	if err != nil {
-		log.Error().Err(err).Msg("error creating thornode client")
-		return nil
-	}
+func NewLiveDataFetcher(client common.ThornodeDataFetcher) *liveDataFetcher {
 	return &liveDataFetcher{
 		client: client,
 	}
@@ -45,11 +40,13 @@ func NewLiveDataFetcher() *liveDataFetcher {
 ////////////////////////////////////////////////////////////////////////////////
 
 type InvariantsMonitor struct {
+	client  common.ThornodeDataFetcher
 	tripped map[string]bool // Map to track which invariants have been previously detected as broken.
 }
 
-func NewInvariantsMonitor() *InvariantsMonitor {
+func NewInvariantsMonitor(client common.ThornodeDataFetcher) *InvariantsMonitor {
 	return &InvariantsMonitor{
+		client:  client,
 		tripped: make(map[string]bool),
 	}
 }
@@ -60,10 +57,12 @@ func (invm *InvariantsMonitor) Name() string {
 func (invm *InvariantsMonitor) Check() ([]notify.Alert, error) {
 	log.Info().Msg("Checking invariants...")
 
-	ldf := NewLiveDataFetcher()
-	if ldf == nil {
-		return nil, fmt.Errorf("error creating live data fetcher")
+	client, height, err := common.PinLatestHeight(invm.client)
+	if err != nil {
+		return nil, err
 	}
+
+	ldf := NewLiveDataFetcher(client)
 	invariants, err := ldf.client.GetInvariants()
 
 	if err != nil {
@@ -83,7 +82,7 @@ func (invm *InvariantsMonitor) Check() ([]notify.Alert, error) {
 			msgs = append(msgs, fmt.Sprintf("> https://thornode.ninerealms.com/thorchain/invariant/%s", b))
 		}
 		// Notify using the configured notification system.
-		return []notify.Alert{{Message: strings.Join(msgs, "\n")}}, nil
+		return []notify.Alert{{Message: strings.Join(msgs, "\n"), Height: height}}, nil
 	}
 
 	return nil, nil
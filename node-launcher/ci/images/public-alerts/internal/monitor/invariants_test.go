@@ -5,6 +5,8 @@ import (
 	"strings"
 	"testing"
 
+	"public-alerts/internal/common"
+
 	"github.com/stretchr/testify/assert"
 	openapi "gitlab.com/thorchain/thornode/openapi/gen"
 )
@@ -80,7 +82,7 @@ func TestInvariantsMonitor_CheckInvariants(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			testDF := setupTestDataFetcher()
-			invm := NewInvariantsMonitor()
+			invm := NewInvariantsMonitor(common.NewFakeClient())
 			invCheck, err := invm.CheckInvariants([]string{tt.invariant}, testDF)
 			if err != nil {
 				if !strings.Contains(err.Error(), tt.wantErrorMsg) {
@@ -98,3 +100,21 @@ func TestInvariantsMonitor_CheckInvariants(t *testing.T) {
 		)
 	}
 }
+
+func TestInvariantsMonitorCheck(t *testing.T) {
+	client := common.NewFakeClient()
+	client.Invariants = setupTestDataFetcher().TestData
+
+	invm := NewInvariantsMonitor(client)
+	alerts, err := invm.Check()
+	assert.NoError(t, err)
+	assert.Len(t, alerts, 1)
+	assert.Contains(t, alerts[0].Message, "/thorchain/invariant/bond")
+	assert.Contains(t, alerts[0].Message, "/thorchain/invariant/streaming_swaps")
+	assert.NotContains(t, alerts[0].Message, "/thorchain/invariant/thorchain")
+
+	// broken invariants are only reported once
+	alerts, err = invm.Check()
+	assert.NoError(t, err)
+	assert.Empty(t, alerts)
+}
@@ -0,0 +1,126 @@
+package monitor
+
+import (
+	"fmt"
+	"public-alerts/internal/common"
+	"public-alerts/internal/config"
+	"public-alerts/internal/notify"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// LivenessMonitor watches THORChain block production through the Tendermint RPC. It
+// alerts when no block has been committed for too long, when the average block time
+// drifts above the expected ~6s, and when the voting power precommitting blocks
+// approaches the 2/3 needed to commit them. Each condition is alerted once and
+// again when it recovers.
+type LivenessMonitor struct {
+	client  common.ThornodeDataFetcher
+	now     func() time.Time
+	tripped map[string]bool // conditions already alerted
+}
+
+func NewLivenessMonitor(client common.ThornodeDataFetcher) *LivenessMonitor {
+	return &LivenessMonitor{
+		client:  client,
+		now:     time.Now,
+		tripped: make(map[string]bool),
+	}
+}
+
+func (lm *LivenessMonitor) Name() string {
+	return "LivenessMonitor"
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// Helpers
+////////////////////////////////////////////////////////////////////////////////
+
+// livenessRecoveries are the messages sent when a condition clears, by condition.
+var livenessRecoveries = map[string]string{
+	"stall":         "### Block Production Resumed",
+	"slow":          "### Block Time Recovered",
+	"participation": "### Validator Participation Recovered",
+}
+
+// participation returns the share of voting power that precommitted the block.
+func participation(commit *common.BlockCommit) float64 {
+	if commit.TotalPower == 0 {
+		return 0
+	}
+	return float64(commit.SignedPower) / float64(commit.TotalPower)
+}
+
+// averageBlockTime returns the mean time between blocks from earlier to latest.
+func averageBlockTime(earlier, latest *common.BlockCommit) time.Duration {
+	blocks := latest.Height - earlier.Height
+	if blocks <= 0 {
+		return 0
+	}
+	return latest.Time.Sub(earlier.Time) / time.Duration(blocks)
+}
+
+// livenessConditions returns the ongoing block production problems keyed by condition.
+// earlier is the commit BlockTimeWindow blocks back and may be nil early in the chain.
+func livenessConditions(latest, earlier *common.BlockCommit, now time.Time, cfg config.LivenessMonitorConfig) map[string]string {
+	conditions := make(map[string]string)
+
+	if since := now.Sub(latest.Time); since > cfg.StallThreshold {
+		conditions["stall"] = fmt.Sprintf("### Block Production Stalled\n> **Last Block:** %d, %s ago (limit %s)",
+			latest.Height, since.Round(time.Second), cfg.StallThreshold)
+	}
+
+	if earlier != nil {
+		avg := averageBlockTime(earlier, latest)
+		if float64(avg) > float64(cfg.ExpectedBlockTime)*cfg.SlowBlockFactor {
+			conditions["slow"] = fmt.Sprintf("### Block Production Slow\n> **Average Block Time:** %s over %d blocks (expected %s)",
+				avg.Round(10*time.Millisecond), latest.Height-earlier.Height, cfg.ExpectedBlockTime)
+		}
+	}
+
+	if share := participation(latest); share < cfg.MinParticipation {
+		conditions["participation"] = fmt.Sprintf("### Low Validator Participation\n> **Precommits:** %.1f%% of voting power, %d/%d validators (warn below %.1f%%, blocks halt below 66.7%%)",
+			share*100, latest.Signers, latest.Validators, cfg.MinParticipation*100)
+	}
+	return conditions
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// Check
+////////////////////////////////////////////////////////////////////////////////
+
+func (lm *LivenessMonitor) Check() ([]notify.Alert, error) {
+	log.Info().Msg("Checking block production...")
+	cfg := config.Get()
+
+	// the latest commit is read directly, the cached latest height may be stale
+	latest, err := lm.client.GetCommit(0)
+	if err != nil {
+		return nil, err
+	}
+	var earlier *common.BlockCommit
+	if window := cfg.LivenessMonitor.BlockTimeWindow; latest.Height > window {
+		earlier, err = lm.client.GetCommit(latest.Height - window)
+		if err != nil {
+			return nil, err
+		}
+	}
+	conditions := livenessConditions(latest, earlier, lm.now(), cfg.LivenessMonitor)
+
+	var alerts []notify.Alert
+	for _, kind := range []string{"stall", "slow", "participation"} {
+		msg, ok := conditions[kind]
+		switch {
+		case ok && !lm.tripped[kind]:
+			lm.tripped[kind] = true
+		case !ok && lm.tripped[kind]:
+			delete(lm.tripped, kind)
+			msg = fmt.Sprintf("%s\n> **Latest Block:** %d", livenessRecoveries[kind], latest.Height)
+		default:
+			continue
+		}
+		alerts = append(alerts, notify.Alert{Webhooks: cfg.Webhooks.Activity, Message: msg, Height: latest.Height})
+	}
+	return alerts, nil
+}
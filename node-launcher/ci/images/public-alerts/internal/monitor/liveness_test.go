@@ -0,0 +1,109 @@
+package monitor
+
+import (
+	"public-alerts/internal/common"
+	"public-alerts/internal/config"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLivenessConditions(t *testing.T) {
+	cfg := config.NewLivenessMonitorConfig()
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	healthy := func(height int, age time.Duration) *common.BlockCommit {
+		return &common.BlockCommit{Height: height, Time: now.Add(-age), Validators: 100, Signers: 95, TotalPower: 100, SignedPower: 95}
+	}
+
+	tests := []struct {
+		name    string
+		latest  *common.BlockCommit
+		earlier *common.BlockCommit
+		want    []string
+	}{
+		{
+			name:    "healthy",
+			latest:  healthy(1000, 3*time.Second),
+			earlier: healthy(900, 603*time.Second),
+		},
+		{
+			name:    "stalled",
+			latest:  healthy(1000, 2*time.Minute),
+			earlier: healthy(900, 12*time.Minute),
+			want:    []string{"stall"},
+		},
+		{
+			name:    "slow",
+			latest:  healthy(1000, 3*time.Second),
+			earlier: healthy(900, 1003*time.Second),
+			want:    []string{"slow"},
+		},
+		{
+			name:   "low participation without history",
+			latest: &common.BlockCommit{Height: 50, Time: now, Validators: 100, Signers: 70, TotalPower: 100, SignedPower: 70},
+			want:   []string{"participation"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conditions := livenessConditions(tt.latest, tt.earlier, now, cfg)
+			if len(conditions) != len(tt.want) {
+				t.Fatalf("expected %v, got %v", tt.want, conditions)
+			}
+			for _, kind := range tt.want {
+				if _, ok := conditions[kind]; !ok {
+					t.Errorf("expected %s condition, got %v", kind, conditions)
+				}
+			}
+		})
+	}
+}
+
+func TestLivenessMonitorCheck(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	client := common.NewFakeClient()
+	client.Height = 1000
+	client.Commits[900] = &common.BlockCommit{Height: 900, Time: now.Add(-10 * time.Minute), TotalPower: 100, SignedPower: 100}
+	client.Commits[1000] = &common.BlockCommit{Height: 1000, Time: now, Validators: 100, Signers: 100, TotalPower: 100, SignedPower: 100}
+
+	lm := NewLivenessMonitor(client)
+	lm.now = func() time.Time { return now }
+	alerts, err := lm.Check()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(alerts) != 0 {
+		t.Fatalf("expected no alerts for healthy block production, got %v", alerts)
+	}
+
+	// no new block for two minutes
+	now = now.Add(2 * time.Minute)
+	alerts, err = lm.Check()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(alerts) != 1 || !strings.Contains(alerts[0].Message, "Block Production Stalled") || alerts[0].Height != 1000 {
+		t.Fatalf("expected a stall alert at height 1000, got %v", alerts)
+	}
+
+	// the stall is only alerted once
+	alerts, err = lm.Check()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(alerts) != 0 {
+		t.Fatalf("expected the ongoing stall not to be repeated, got %v", alerts)
+	}
+
+	// blocks resume
+	client.Height = 1001
+	client.Commits[901] = &common.BlockCommit{Height: 901, Time: now.Add(-10 * time.Minute), TotalPower: 100, SignedPower: 100}
+	client.Commits[1001] = &common.BlockCommit{Height: 1001, Time: now, Validators: 100, Signers: 100, TotalPower: 100, SignedPower: 100}
+	alerts, err = lm.Check()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(alerts) != 1 || !strings.Contains(alerts[0].Message, "Block Production Resumed") {
+		t.Fatalf("expected a resumed alert, got %v", alerts)
+	}
+}
@@ -0,0 +1,189 @@
+package monitor
+
+import (
+	"fmt"
+	"public-alerts/internal/common"
+	"public-alerts/internal/config"
+	"public-alerts/internal/notify"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+	openapi "gitlab.com/thorchain/thornode/openapi/gen"
+)
+
+// MigrationMonitor follows the balances left in each retiring asgard vault while a
+// churn migrates them to the new vaults. It estimates the time to completion from
+// the rate the vault's USD value is draining, and alerts when a vault has made no
+// progress for the configured number of blocks or still holds funds after the
+// expected migration window.
+type MigrationMonitor struct {
+	client common.ThornodeDataFetcher
+	prices common.PriceFetcher
+	vaults map[string]*migrationProgress // by retiring vault pubkey
+}
+
+// migrationProgress is the migration state of one retiring vault.
+type migrationProgress struct {
+	since        int                // height the vault started retiring
+	firstHeight  int                // height the vault was first seen retiring
+	firstValue   float64            // USD value of the vault when first seen
+	lastProgress int                // height a balance last decreased
+	remaining    map[string]float64 // balances by asset at the last check
+	stalled      bool
+	overdue      bool
+}
+
+func NewMigrationMonitor(client common.ThornodeDataFetcher, prices common.PriceFetcher) *MigrationMonitor {
+	return &MigrationMonitor{
+		client: client,
+		prices: prices,
+		vaults: make(map[string]*migrationProgress),
+	}
+}
+
+func (mm *MigrationMonitor) Name() string {
+	return "MigrationMonitor"
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// Helpers
+////////////////////////////////////////////////////////////////////////////////
+
+// vaultBalances returns the non-zero balances of a vault by asset.
+func vaultBalances(vault openapi.Vault) map[string]float64 {
+	balances := make(map[string]float64)
+	for _, coin := range vault.Coins {
+		if amount, err := strconv.ParseFloat(coin.Amount, 64); err == nil && amount > 0 {
+			balances[coin.Asset] = amount
+		}
+	}
+	return balances
+}
+
+func balancesUSD(balances map[string]float64, prices map[string]float64) float64 {
+	total := 0.0
+	for asset, amount := range balances {
+		total += amount / 1e8 * prices[asset]
+	}
+	return total
+}
+
+// madeProgress reports whether any balance decreased since prev.
+func madeProgress(prev, balances map[string]float64) bool {
+	for asset, amount := range prev {
+		if balances[asset] < amount {
+			return true
+		}
+	}
+	return false
+}
+
+// migrationETA estimates the blocks left to drain value at the rate it drained
+// since the vault was first seen, returning false when no progress was made yet.
+func migrationETA(p *migrationProgress, value float64, height int) (int, bool) {
+	drained, blocks := p.firstValue-value, height-p.firstHeight
+	if drained <= 0 || blocks <= 0 {
+		return 0, false
+	}
+	return int(value / (drained / float64(blocks))), true
+}
+
+// describeMigration lists the progress, estimate and remaining balances of a vault.
+func describeMigration(p *migrationProgress, balances, prices map[string]float64, height int) []string {
+	value := balancesUSD(balances, prices)
+	lines := []string{fmt.Sprintf("> **Retiring Since:** %d (%d blocks)", p.since, height-p.since)}
+	if p.firstValue > 0 {
+		lines = append(lines, fmt.Sprintf("> **Migrated:** %s of %s since first seen", common.FormatPercent(1-value/p.firstValue), common.FormatUSD(p.firstValue)))
+	}
+	if eta, ok := migrationETA(p, value, height); ok {
+		lines = append(lines, fmt.Sprintf("> **Estimated Completion:** %d blocks (~%.1f hours)", eta, float64(eta)/blocksPerHour))
+	} else {
+		lines = append(lines, "> **Estimated Completion:** unknown, no progress yet")
+	}
+	assets := make([]string, 0, len(balances))
+	for asset := range balances {
+		assets = append(assets, asset)
+	}
+	sort.Strings(assets)
+	for _, asset := range assets {
+		lines = append(lines, fmt.Sprintf("> **Remaining:** %.4f `%s` (%s)", balances[asset]/1e8, asset, common.FormatUSD(balances[asset]/1e8*prices[asset])))
+	}
+	return lines
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// Check
+////////////////////////////////////////////////////////////////////////////////
+
+func (mm *MigrationMonitor) Check() ([]notify.Alert, error) {
+	log.Info().Msg("Checking vault migrations...")
+	cfg := config.Get()
+
+	client, height, err := common.PinLatestHeight(mm.client)
+	if err != nil {
+		return nil, err
+	}
+	vaults, err := client.GetVaults()
+	if err != nil {
+		return nil, err
+	}
+	retiring := retiringVaults(vaults)
+	if len(retiring) == 0 {
+		mm.vaults = make(map[string]*migrationProgress)
+		return nil, nil
+	}
+	prices, err := mm.prices.GetAssetPricesUSD()
+	if err != nil {
+		return nil, err
+	}
+
+	var alerts []notify.Alert
+	current := make(map[string]bool, len(retiring))
+	for _, vault := range retiring {
+		pubKey := vault.GetPubKey()
+		current[pubKey] = true
+		balances := vaultBalances(vault)
+
+		p, ok := mm.vaults[pubKey]
+		if !ok {
+			p = &migrationProgress{
+				since:        churnStartHeight([]openapi.Vault{vault}, height),
+				firstHeight:  height,
+				firstValue:   balancesUSD(balances, prices),
+				lastProgress: height,
+			}
+			mm.vaults[pubKey] = p
+		} else if madeProgress(p.remaining, balances) {
+			p.lastProgress = height
+			p.stalled = false
+		}
+		p.remaining = balances
+		if len(balances) == 0 {
+			continue // migrated, the vault retires once its outbounds are observed
+		}
+
+		var title string
+		switch {
+		case !p.stalled && height-p.lastProgress > cfg.MigrationMonitor.StallBlocks:
+			p.stalled = true
+			title = fmt.Sprintf("### Vault Migration Stalled: `%s`\n> **No Progress:** %d blocks (limit %d)",
+				common.ShortenPubKey(pubKey), height-p.lastProgress, cfg.MigrationMonitor.StallBlocks)
+		case !p.overdue && height-p.since > cfg.MigrationMonitor.ExpectedWindowBlocks:
+			p.overdue = true
+			title = fmt.Sprintf("### Retiring Vault Overdue: `%s`\n> **Expected Within:** %d blocks",
+				common.ShortenPubKey(pubKey), cfg.MigrationMonitor.ExpectedWindowBlocks)
+		default:
+			continue
+		}
+		msg := strings.Join(append([]string{title}, describeMigration(p, balances, prices, height)...), "\n")
+		alerts = append(alerts, notify.Alert{Webhooks: cfg.Webhooks.Activity, Message: msg, Height: height})
+	}
+	for pubKey := range mm.vaults {
+		if !current[pubKey] {
+			delete(mm.vaults, pubKey)
+		}
+	}
+	return alerts, nil
+}
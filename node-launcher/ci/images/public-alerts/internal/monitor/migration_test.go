@@ -0,0 +1,88 @@
+package monitor
+
+import (
+	"public-alerts/internal/common"
+	"strings"
+	"testing"
+
+	openapi "gitlab.com/thorchain/thornode/openapi/gen"
+)
+
+func retiringVault(pubKey string, since int64, coins ...openapi.Coin) openapi.Vault {
+	return openapi.Vault{PubKey: &pubKey, Status: "RetiringVault", StatusSince: &since, Coins: coins}
+}
+
+func TestMigrationETA(t *testing.T) {
+	p := &migrationProgress{firstHeight: 1000, firstValue: 1000}
+	if _, ok := migrationETA(p, 1000, 1100); ok {
+		t.Error("expected no estimate without progress")
+	}
+	// 400 drained in 200 blocks leaves 600 at 2 per block
+	if eta, ok := migrationETA(p, 600, 1200); !ok || eta != 300 {
+		t.Errorf("expected 300 blocks, got %d (%v)", eta, ok)
+	}
+}
+
+func TestMigrationMonitorCheck(t *testing.T) {
+	client := common.NewFakeClient()
+	client.Height = 1000
+	client.Prices = map[string]float64{"BTC.BTC": 50000, "ETH.ETH": 2000}
+	client.Vaults = []openapi.Vault{
+		retiringVault("thorpubRETIRING", 1000,
+			openapi.Coin{Asset: "BTC.BTC", Amount: "1000000000"},
+			openapi.Coin{Asset: "ETH.ETH", Amount: "10000000000"}),
+	}
+
+	mm := NewMigrationMonitor(client, client)
+	alerts, err := mm.Check()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(alerts) != 0 {
+		t.Fatalf("expected no alerts when the migration starts, got %v", alerts)
+	}
+
+	// half the BTC migrates, then nothing moves
+	client.Height = 1300
+	client.Vaults[0].Coins = []openapi.Coin{{Asset: "BTC.BTC", Amount: "500000000"}, {Asset: "ETH.ETH", Amount: "10000000000"}}
+	if alerts, err = mm.Check(); err != nil || len(alerts) != 0 {
+		t.Fatalf("expected progress without alerts, got %v, %v", alerts, err)
+	}
+
+	client.Height = 2300
+	alerts, err = mm.Check()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(alerts) != 1 {
+		t.Fatalf("expected a stall alert, got %v", alerts)
+	}
+	expected := strings.Join([]string{
+		"### Vault Migration Stalled: `RING`",
+		"> **No Progress:** 1000 blocks (limit 900)",
+		"> **Retiring Since:** 1000 (1300 blocks)",
+		"> **Migrated:** 35.71% of $700000 since first seen",
+		"> **Estimated Completion:** 2340 blocks (~3.9 hours)",
+		"> **Remaining:** 5.0000 `BTC.BTC` ($250000)",
+		"> **Remaining:** 100.0000 `ETH.ETH` ($200000)",
+	}, "\n")
+	if alerts[0].Message != expected {
+		t.Errorf("expected %q, got %q", expected, alerts[0].Message)
+	}
+
+	// past the expected window the vault is overdue
+	client.Height = 4700
+	alerts, err = mm.Check()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(alerts) != 1 || !strings.HasPrefix(alerts[0].Message, "### Retiring Vault Overdue: `RING`") {
+		t.Fatalf("expected an overdue alert, got %v", alerts)
+	}
+
+	// once migrated and retired the vault is forgotten
+	client.Vaults = nil
+	if alerts, err = mm.Check(); err != nil || len(alerts) != 0 || len(mm.vaults) != 0 {
+		t.Fatalf("expected the retired vault to be forgotten, got %v, %v", alerts, err)
+	}
+}
@@ -0,0 +1,212 @@
+package monitor
+
+import (
+	"fmt"
+	"public-alerts/internal/common"
+	"public-alerts/internal/config"
+	"public-alerts/internal/notify"
+	"sort"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+	openapi "gitlab.com/thorchain/thornode/openapi/gen"
+)
+
+// MimirMonitor snapshots the effective mimir each poll and alerts on added, removed
+// and changed keys, attributing each change to the admin or to a node vote. It also
+// warns when a node-voted value is close to reaching supermajority.
+type MimirMonitor struct {
+	client      common.ThornodeDataFetcher
+	initialized bool
+	last        map[string]int64 // effective mimir at the last check
+	lastAdmin   map[string]int64 // admin mimir at the last check
+	tripped     map[string]bool  // "key=value" votes already warned about
+}
+
+func NewMimirMonitor(client common.ThornodeDataFetcher) *MimirMonitor {
+	return &MimirMonitor{
+		client:    client,
+		last:      make(map[string]int64),
+		lastAdmin: make(map[string]int64),
+		tripped:   make(map[string]bool),
+	}
+}
+
+func (mm *MimirMonitor) Name() string {
+	return "MimirMonitor"
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// Helpers
+////////////////////////////////////////////////////////////////////////////////
+
+// mimirSeverity classifies a mimir key by the impact of changing it.
+func mimirSeverity(key string) string {
+	switch {
+	case strings.Contains(key, "HALT"), strings.Contains(key, "PAUSE"), strings.HasPrefix(key, "STOPSOLVENCYCHECK"):
+		return "critical"
+	case strings.Contains(key, "CAP"), strings.HasPrefix(key, "MAX"), strings.Contains(key, "BOND"), strings.Contains(key, "CHURN"):
+		return "high"
+	case strings.Contains(key, "FEE"):
+		return "medium"
+	}
+	return "low"
+}
+
+// supermajority returns the number of votes needed for 2/3 of total.
+func supermajority(total int) int {
+	return (2*total + 2) / 3
+}
+
+// tallyMimirVotes counts the votes of active nodes per key and value.
+func tallyMimirVotes(votes []openapi.MimirVote, active map[string]bool) map[string]map[int64]int {
+	tally := make(map[string]map[int64]int)
+	for _, vote := range votes {
+		if vote.Key == nil || vote.Value == nil || !active[vote.GetSigner()] {
+			continue
+		}
+		if tally[*vote.Key] == nil {
+			tally[*vote.Key] = make(map[int64]int)
+		}
+		tally[*vote.Key][*vote.Value]++
+	}
+	return tally
+}
+
+// mimirSource attributes the effective value of key to the admin or a node vote.
+func mimirSource(key string, value int64, admin map[string]int64, tally map[string]map[int64]int, activeCount int) string {
+	if v, ok := admin[key]; ok && v == value {
+		return "admin"
+	}
+	if activeCount > 0 && tally[key][value] >= supermajority(activeCount) {
+		return "node vote"
+	}
+	return "unknown"
+}
+
+func formatMimirValue(value int64, ok bool) string {
+	if !ok {
+		return "unset"
+	}
+	return fmt.Sprintf("`%d`", value)
+}
+
+// diffMimir describes the keys added, removed and changed between two snapshots.
+func diffMimir(prev, prevAdmin, mimir, admin map[string]int64, tally map[string]map[int64]int, activeCount int) []string {
+	keys := make(map[string]bool)
+	for key := range prev {
+		keys[key] = true
+	}
+	for key := range mimir {
+		keys[key] = true
+	}
+	sorted := make([]string, 0, len(keys))
+	for key := range keys {
+		sorted = append(sorted, key)
+	}
+	sort.Strings(sorted)
+
+	var lines []string
+	for _, key := range sorted {
+		old, hadOld := prev[key]
+		value, hasValue := mimir[key]
+		if hadOld == hasValue && old == value {
+			continue
+		}
+		var source string
+		if hasValue {
+			source = mimirSource(key, value, admin, tally, activeCount)
+		} else if _, ok := prevAdmin[key]; ok {
+			source = "admin"
+		} else {
+			source = "node vote"
+		}
+		lines = append(lines, fmt.Sprintf("> **[%s]** `%s`: %s → %s (%s)",
+			mimirSeverity(key), key, formatMimirValue(old, hadOld), formatMimirValue(value, hasValue), source))
+	}
+	return lines
+}
+
+// nearSupermajority returns the node-voted values that are within warnVotes of
+// passing and would change the effective mimir, keyed by "key=value".
+func nearSupermajority(mimir map[string]int64, tally map[string]map[int64]int, activeCount, warnVotes int) map[string]string {
+	needed := supermajority(activeCount)
+	near := make(map[string]string)
+	for key, values := range tally {
+		for value, count := range values {
+			remaining := needed - count
+			if remaining <= 0 || remaining > warnVotes {
+				continue
+			}
+			if current, ok := mimir[key]; ok && current == value {
+				continue
+			}
+			near[fmt.Sprintf("%s=%d", key, value)] = fmt.Sprintf("> **[%s]** `%s` = `%d`: %d/%d votes, %d more to pass",
+				mimirSeverity(key), key, value, count, activeCount, remaining)
+		}
+	}
+	return near
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// Check
+////////////////////////////////////////////////////////////////////////////////
+
+func (mm *MimirMonitor) Check() ([]notify.Alert, error) {
+	log.Info().Msg("Checking mimir...")
+	cfg := config.Get()
+
+	client, height, err := common.PinLatestHeight(mm.client)
+	if err != nil {
+		return nil, err
+	}
+	mimir, err := client.GetMimir()
+	if err != nil {
+		return nil, err
+	}
+	admin, err := client.GetAdminMimir()
+	if err != nil {
+		return nil, err
+	}
+	votes, err := client.GetMimirVotes()
+	if err != nil {
+		return nil, err
+	}
+	nodes, err := client.GetNodes()
+	if err != nil {
+		return nil, err
+	}
+	active := activeSet(nodes)
+	tally := tallyMimirVotes(votes, active)
+
+	var alerts []notify.Alert
+	if mm.initialized {
+		if lines := diffMimir(mm.last, mm.lastAdmin, mimir, admin, tally, len(active)); len(lines) > 0 {
+			msg := "### Mimir Changed\n" + strings.Join(lines, "\n")
+			alerts = append(alerts, notify.Alert{Webhooks: cfg.Webhooks.Activity, Message: msg, Height: height})
+		}
+	}
+	mm.initialized = true
+	mm.last = mimir
+	mm.lastAdmin = admin
+
+	near := nearSupermajority(mimir, tally, len(active), cfg.MimirMonitor.SupermajorityWarningVotes)
+	var lines []string
+	for key, line := range near {
+		if !mm.tripped[key] {
+			lines = append(lines, line)
+			mm.tripped[key] = true
+		}
+	}
+	for key := range mm.tripped {
+		if _, ok := near[key]; !ok {
+			delete(mm.tripped, key)
+		}
+	}
+	if len(lines) > 0 {
+		sort.Strings(lines)
+		msg := "### Mimir Vote Nearing Supermajority\n" + strings.Join(lines, "\n")
+		alerts = append(alerts, notify.Alert{Webhooks: cfg.Webhooks.Activity, Message: msg, Height: height})
+	}
+	return alerts, nil
+}
@@ -0,0 +1,105 @@
+package monitor
+
+import (
+	"fmt"
+	"public-alerts/internal/common"
+	"strings"
+	"testing"
+
+	openapi "gitlab.com/thorchain/thornode/openapi/gen"
+)
+
+func TestMimirSeverity(t *testing.T) {
+	tests := map[string]string{
+		"HALTETHCHAIN":           "critical",
+		"SOLVENCYHALTBTCCHAIN":   "critical",
+		"PAUSELP":                "critical",
+		"STOPSOLVENCYCHECKETH":   "critical",
+		"SYNTHYIELDCAP":          "high",
+		"MAXSYNTHPERPOOLDEPTH":   "high",
+		"MINIMUMBONDINRUNE":      "high",
+		"OUTBOUNDTRANSACTIONFEE": "medium",
+		"ENABLESAVINGSVAULTS":    "low",
+	}
+	for key, want := range tests {
+		if got := mimirSeverity(key); got != want {
+			t.Errorf("mimirSeverity(%s) = %s, want %s", key, got, want)
+		}
+	}
+}
+
+func TestSupermajority(t *testing.T) {
+	for total, want := range map[int]int{3: 2, 4: 3, 99: 66, 100: 67} {
+		if got := supermajority(total); got != want {
+			t.Errorf("supermajority(%d) = %d, want %d", total, got, want)
+		}
+	}
+}
+
+func mimirVote(key string, value int64, signer string) openapi.MimirVote {
+	return openapi.MimirVote{Key: &key, Value: &value, Signer: &signer}
+}
+
+func TestMimirMonitorCheck(t *testing.T) {
+	client := common.NewFakeClient()
+	client.Height = 100
+	for i := 0; i < 9; i++ {
+		client.Nodes = append(client.Nodes, openapi.Node{NodeAddress: fmt.Sprintf("thor1node%d", i), Status: "Active"})
+	}
+	client.Mimir = map[string]int64{"HALTETHCHAIN": 0, "MAXSYNTHPERPOOLDEPTH": 1500}
+	client.AdminMimir = map[string]int64{"HALTETHCHAIN": 0}
+
+	mm := NewMimirMonitor(client)
+	alerts, err := mm.Check()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(alerts) != 0 {
+		t.Fatalf("expected the first check to only record state, got %v", alerts)
+	}
+
+	// admin halts ETH, nodes vote in a fee, a key is removed and a vote nears supermajority
+	client.Height = 110
+	client.Mimir = map[string]int64{"HALTETHCHAIN": 1, "OUTBOUNDTRANSACTIONFEE": 2000000}
+	client.AdminMimir = map[string]int64{"HALTETHCHAIN": 1}
+	client.MimirVotes = nil
+	for i := 0; i < 6; i++ {
+		client.MimirVotes = append(client.MimirVotes, mimirVote("OUTBOUNDTRANSACTIONFEE", 2000000, fmt.Sprintf("thor1node%d", i)))
+	}
+	for i := 0; i < 4; i++ {
+		client.MimirVotes = append(client.MimirVotes, mimirVote("PAUSELP", 1, fmt.Sprintf("thor1node%d", i)))
+	}
+	client.MimirVotes = append(client.MimirVotes, mimirVote("PAUSELP", 1, "thor1notactive"))
+
+	alerts, err = mm.Check()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(alerts) != 2 {
+		t.Fatalf("expected change and supermajority alerts, got %v", alerts)
+	}
+	for _, want := range []string{
+		"**[critical]** `HALTETHCHAIN`: `0` → `1` (admin)",
+		"**[high]** `MAXSYNTHPERPOOLDEPTH`: `1500` → unset (node vote)",
+		"**[medium]** `OUTBOUNDTRANSACTIONFEE`: unset → `2000000` (node vote)",
+	} {
+		if !strings.Contains(alerts[0].Message, want) {
+			t.Errorf("expected change alert to contain %q, got:\n%s", want, alerts[0].Message)
+		}
+	}
+	if !strings.Contains(alerts[1].Message, "**[critical]** `PAUSELP` = `1`: 4/9 votes, 2 more to pass") {
+		t.Errorf("unexpected supermajority alert: %s", alerts[1].Message)
+	}
+	if alerts[0].Height != 110 || alerts[1].Height != 110 {
+		t.Errorf("expected alerts at height 110, got %d and %d", alerts[0].Height, alerts[1].Height)
+	}
+
+	// unchanged state does not alert again
+	alerts, err = mm.Check()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(alerts) != 0 {
+		t.Errorf("expected no repeated alerts, got %v", alerts)
+	}
+}
@@ -1,8 +1,10 @@
 package monitor
 
 import (
+	"context"
 	"fmt"
 	"public-alerts/internal/config"
+	"public-alerts/internal/events"
 	"public-alerts/internal/notify"
 	"time"
 
@@ -48,3 +50,58 @@ func Spawn(m Monitor, alertQueue chan<- notify.Alert, interval time.Duration) {
 		}
 	}()
 }
+
+// EventMonitor reacts to THORChain events as they are emitted instead of polling.
+type EventMonitor interface {
+	HandleEvent(event events.Event) ([]notify.Alert, error)
+	EventTypes() []string // event types the monitor is interested in
+	Name() string
+}
+
+// SpawnEventMonitors streams events from source in a goroutine and dispatches them
+// to the monitors interested in each event type.
+func SpawnEventMonitors(source events.Source, alertQueue chan<- notify.Alert, monitors ...EventMonitor) {
+	go func() {
+		// avoid swallowing panic
+		defer func() {
+			if rec := recover(); rec != nil {
+				err_msg := fmt.Sprintf("```[ERROR] public-alerts: Event monitors panicked: %v ```", rec)
+				alertQueue <- notify.Alert{Webhooks: config.Get().Webhooks.Errors, Message: err_msg}
+				log.Fatal().Msg(err_msg)
+			}
+		}()
+
+		err := source.Run(context.Background(), dispatchEvents(monitors, alertQueue))
+
+		// live sources reconnect on their own, so returning means the stream has ended
+		err_msg := fmt.Sprintf("```[ERROR] public-alerts: Event stream stopped: %v```", err)
+		log.Error().Err(err).Msg(err_msg)
+		alertQueue <- notify.Alert{Webhooks: config.Get().Webhooks.Errors, Message: err_msg}
+	}()
+}
+
+// dispatchEvents returns an event handler routing each event to the interested monitors.
+func dispatchEvents(monitors []EventMonitor, alertQueue chan<- notify.Alert) events.Handler {
+	byType := make(map[string][]EventMonitor)
+	for _, m := range monitors {
+		for _, t := range m.EventTypes() {
+			byType[t] = append(byType[t], m)
+		}
+	}
+
+	return func(event events.Event) {
+		for _, m := range byType[event.Type] {
+			alerts, err := m.HandleEvent(event)
+
+			if err != nil {
+				err_msg := fmt.Sprintf("```[ERROR] public-alerts: Error handling %s event at %d in monitor %s: %v```", event.Type, event.Height, m.Name(), err)
+				log.Error().Err(err).Msg(err_msg)
+				alertQueue <- notify.Alert{Webhooks: config.Get().Webhooks.Errors, Message: err_msg}
+			}
+
+			for _, alert := range alerts {
+				alertQueue <- alert
+			}
+		}
+	}
+}
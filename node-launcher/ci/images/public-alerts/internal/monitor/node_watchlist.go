@@ -0,0 +1,215 @@
+package monitor
+
+import (
+	"fmt"
+	"public-alerts/internal/common"
+	"public-alerts/internal/config"
+	"public-alerts/internal/notify"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+	openapi "gitlab.com/thorchain/thornode/openapi/gen"
+)
+
+// blocksPerHour is the number of THORChain blocks produced in an hour at ~6s per block.
+const blocksPerHour = 600
+
+// NodeWatchlistMonitor follows the operator's own nodes and alerts each node's
+// receivers about status transitions, jailing, leaving, bond changes, slash point
+// accrual and observation problems relative to the rest of the network.
+type NodeWatchlistMonitor struct {
+	client  common.ThornodeDataFetcher
+	watched []config.WatchedNode
+	last    map[string]watchedSnapshot // previous state by node address
+	tripped map[string]bool            // "<address>/<finding>" conditions already alerted
+}
+
+type watchedSnapshot struct {
+	height int
+	node   openapi.Node
+}
+
+func NewNodeWatchlistMonitor(client common.ThornodeDataFetcher, watched []config.WatchedNode) *NodeWatchlistMonitor {
+	return &NodeWatchlistMonitor{
+		client:  client,
+		watched: watched,
+		last:    make(map[string]watchedSnapshot),
+		tripped: make(map[string]bool),
+	}
+}
+
+func (nwm *NodeWatchlistMonitor) Name() string {
+	return "NodeWatchlistMonitor"
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// Helpers
+////////////////////////////////////////////////////////////////////////////////
+
+func isJailed(node openapi.Node, height int) bool {
+	return int(node.Jail.GetReleaseHeight()) > height
+}
+
+func formatBond(bond string) string {
+	amount, err := strconv.ParseFloat(bond, 64)
+	if err != nil {
+		return bond
+	}
+	return fmt.Sprintf("%.0f RUNE", amount/1e8)
+}
+
+// observationMedians returns the median observed height of each chain across active nodes.
+func observationMedians(nodes []openapi.Node) map[string]float64 {
+	heights := make(map[string][]float64)
+	for _, node := range nodes {
+		if node.Status != "Active" {
+			continue
+		}
+		for _, c := range node.ObserveChains {
+			heights[c.Chain] = append(heights[c.Chain], float64(c.Height))
+		}
+	}
+	medians := make(map[string]float64, len(heights))
+	for chain, h := range heights {
+		medians[chain] = median(h)
+	}
+	return medians
+}
+
+// nodeTransitions describes what changed on a watched node since the previous check.
+func nodeTransitions(prev watchedSnapshot, node openapi.Node, height int) []string {
+	var msgs []string
+	if prev.node.Status != node.Status {
+		msgs = append(msgs, fmt.Sprintf("> **Status:** `%s` → `%s`", prev.node.Status, node.Status))
+	}
+	if isJailed(node, height) && !isJailed(prev.node, prev.height) {
+		msgs = append(msgs, fmt.Sprintf("> **Jailed:** until %d (%s)", node.Jail.GetReleaseHeight(), node.Jail.GetReason()))
+	}
+	if node.RequestedToLeave && !prev.node.RequestedToLeave {
+		msgs = append(msgs, "> **Requested To Leave**")
+	}
+	if node.ForcedToLeave && !prev.node.ForcedToLeave {
+		msgs = append(msgs, "> **Forced To Leave**")
+	}
+	if node.TotalBond != prev.node.TotalBond {
+		msgs = append(msgs, fmt.Sprintf("> **Bond:** %s → %s", formatBond(prev.node.TotalBond), formatBond(node.TotalBond)))
+	}
+	return msgs
+}
+
+// nodeConditions returns the ongoing problems of a watched node keyed by finding,
+// so each is alerted once until it clears.
+func nodeConditions(prev *watchedSnapshot, node openapi.Node, height int, medians map[string]float64, cfg config.Config) map[string]string {
+	conditions := make(map[string]string)
+
+	// slash points reset at churn, so only a growing count is rated
+	if prev != nil && height > prev.height && node.SlashPoints >= prev.node.SlashPoints {
+		rate := (node.SlashPoints - prev.node.SlashPoints) * blocksPerHour / int64(height-prev.height)
+		if rate > cfg.NodeWatchlistMonitor.MaxSlashPointsPerHour {
+			conditions["slash"] = fmt.Sprintf("> **Slash Points:** %d per hour (limit %d), %d total", rate, cfg.NodeWatchlistMonitor.MaxSlashPointsPerHour, node.SlashPoints)
+		}
+	}
+
+	// only active nodes are expected to observe every chain
+	if node.Status != "Active" {
+		return conditions
+	}
+	observed := make(map[string]int64, len(node.ObserveChains))
+	for _, c := range node.ObserveChains {
+		observed[c.Chain] = c.Height
+	}
+	for chain, networkHeight := range medians {
+		h, ok := observed[chain]
+		if !ok {
+			conditions["missing/"+chain] = fmt.Sprintf("> **Not Observing:** `%s`", chain)
+			continue
+		}
+		maxLag, ok := cfg.ChainLagMonitor.MaxChainLag[chain]
+		if lag := int(networkHeight) - int(h); ok && lag > maxLag {
+			conditions["lag/"+chain] = fmt.Sprintf("> **Observation Lag:** `%s` %d blocks behind the network (limit %d)", chain, lag, maxLag)
+		}
+	}
+	return conditions
+}
+
+// watchedNodeWebhooks returns the node's own receivers, or the fallback when it has none.
+func watchedNodeWebhooks(watched config.WatchedNode, fallback config.Webhooks) config.Webhooks {
+	if watched.Webhooks.Slack == "" && watched.Webhooks.Discord == "" && watched.Webhooks.PagerDuty == "" {
+		return fallback
+	}
+	return watched.Webhooks
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// Check
+////////////////////////////////////////////////////////////////////////////////
+
+func (nwm *NodeWatchlistMonitor) Check() ([]notify.Alert, error) {
+	if len(nwm.watched) == 0 {
+		return nil, nil
+	}
+	log.Info().Msg("Checking watched nodes...")
+	cfg := config.Get()
+
+	client, height, err := common.PinLatestHeight(nwm.client)
+	if err != nil {
+		return nil, err
+	}
+	nodes, err := client.GetNodes()
+	if err != nil {
+		return nil, err
+	}
+	byAddress := make(map[string]openapi.Node, len(nodes))
+	for _, node := range nodes {
+		byAddress[node.NodeAddress] = node
+	}
+	medians := observationMedians(nodes)
+
+	var alerts []notify.Alert
+	for _, watched := range nwm.watched {
+		var msgs []string
+		conditions := make(map[string]string)
+
+		node, found := byAddress[watched.Address]
+		if !found {
+			conditions["missing"] = "> **Not Found** in the node list"
+		} else {
+			prev, seen := nwm.last[watched.Address]
+			if seen {
+				msgs = append(msgs, nodeTransitions(prev, node, height)...)
+				conditions = nodeConditions(&prev, node, height, medians, cfg)
+			} else {
+				conditions = nodeConditions(nil, node, height, medians, cfg)
+			}
+			nwm.last[watched.Address] = watchedSnapshot{height: height, node: node}
+		}
+
+		// alert new conditions in a stable order and clear the ones that resolved
+		var kinds []string
+		for kind := range conditions {
+			kinds = append(kinds, kind)
+		}
+		sort.Strings(kinds)
+		for _, kind := range kinds {
+			key := watched.Address + "/" + kind
+			if !nwm.tripped[key] {
+				msgs = append(msgs, conditions[kind])
+				nwm.tripped[key] = true
+			}
+		}
+		prefix := watched.Address + "/"
+		for key := range nwm.tripped {
+			if kind, ok := strings.CutPrefix(key, prefix); ok && conditions[kind] == "" {
+				delete(nwm.tripped, key)
+			}
+		}
+
+		if len(msgs) > 0 {
+			msg := fmt.Sprintf("### Watched Node `%s`\n%s", watched.Address, strings.Join(msgs, "\n"))
+			alerts = append(alerts, notify.Alert{Webhooks: watchedNodeWebhooks(watched, cfg.Webhooks.Activity), Message: msg, Height: height})
+		}
+	}
+	return alerts, nil
+}
@@ -0,0 +1,99 @@
+package monitor
+
+import (
+	"public-alerts/internal/common"
+	"public-alerts/internal/config"
+	"strings"
+	"testing"
+
+	openapi "gitlab.com/thorchain/thornode/openapi/gen"
+)
+
+func TestNodeConditions(t *testing.T) {
+	cfg := config.Config{
+		ChainLagMonitor:      config.ChainLagMonitorConfig{MaxChainLag: map[string]int{"BTC": 3, "ETH": 70}},
+		NodeWatchlistMonitor: config.NodeWatchlistMonitorConfig{MaxSlashPointsPerHour: 600},
+	}
+	medians := map[string]float64{"BTC": 100, "ETH": 1000, "GAIA": 50}
+	node := openapi.Node{
+		Status:        "Active",
+		SlashPoints:   2000,
+		ObserveChains: []openapi.ChainHeight{{Chain: "BTC", Height: 90}, {Chain: "ETH", Height: 990}},
+	}
+	prev := &watchedSnapshot{height: 900, node: openapi.Node{SlashPoints: 1000}}
+
+	conditions := nodeConditions(prev, node, 1000, medians, cfg)
+	if len(conditions) != 3 {
+		t.Fatalf("expected slash, BTC lag and missing GAIA, got %v", conditions)
+	}
+	if !strings.Contains(conditions["slash"], "6000 per hour") {
+		t.Errorf("unexpected slash finding: %s", conditions["slash"])
+	}
+	if !strings.Contains(conditions["lag/BTC"], "10 blocks behind") {
+		t.Errorf("unexpected lag finding: %s", conditions["lag/BTC"])
+	}
+	if _, ok := conditions["missing/GAIA"]; !ok {
+		t.Errorf("expected missing GAIA observation, got %v", conditions)
+	}
+
+	// slash points reset at churn and standby nodes don't observe
+	node.Status = "Standby"
+	prev.node.SlashPoints = 5000
+	if conditions := nodeConditions(prev, node, 1000, medians, cfg); len(conditions) != 0 {
+		t.Errorf("expected no conditions, got %v", conditions)
+	}
+}
+
+func TestNodeWatchlistMonitorCheck(t *testing.T) {
+	own := config.WatchedNode{Address: "thor1mine", Webhooks: config.Webhooks{Discord: "https://discord.example/mine"}}
+	other := config.WatchedNode{Address: "thor1gone"}
+
+	client := common.NewFakeClient()
+	client.Height = 1000
+	peer := openapi.Node{NodeAddress: "thor1peer", Status: "Active", ObserveChains: []openapi.ChainHeight{{Chain: "BTC", Height: 100}}}
+	mine := openapi.Node{NodeAddress: "thor1mine", Status: "Standby", TotalBond: "100000000000000"}
+	client.Nodes = []openapi.Node{peer, mine}
+
+	nwm := NewNodeWatchlistMonitor(client, []config.WatchedNode{own, other})
+
+	alerts, err := nwm.Check()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(alerts) != 1 || !strings.Contains(alerts[0].Message, "`thor1gone`") || !strings.Contains(alerts[0].Message, "Not Found") {
+		t.Fatalf("expected a not found alert for the missing node, got %v", alerts)
+	}
+
+	// our node is churned in with a smaller bond, jailed and not observing BTC
+	releaseHeight, reason := int64(1500), "failed keygen"
+	mine.Status = "Active"
+	mine.TotalBond = "90000000000000"
+	mine.Jail = openapi.NodeJail{ReleaseHeight: &releaseHeight, Reason: &reason}
+	client.Height = 1010
+	client.Nodes = []openapi.Node{peer, mine}
+
+	alerts, err = nwm.Check()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(alerts) != 1 {
+		t.Fatalf("expected 1 alert, got %d", len(alerts))
+	}
+	if alerts[0].Webhooks.Discord != own.Webhooks.Discord || alerts[0].Height != 1010 {
+		t.Errorf("expected alert to the node's receiver at height 1010, got %+v", alerts[0])
+	}
+	for _, want := range []string{"`Standby` → `Active`", "**Jailed:** until 1500 (failed keygen)", "**Bond:** 1000000 RUNE → 900000 RUNE", "**Not Observing:** `BTC`"} {
+		if !strings.Contains(alerts[0].Message, want) {
+			t.Errorf("expected message to contain %q, got:\n%s", want, alerts[0].Message)
+		}
+	}
+
+	// nothing changed, ongoing conditions are not repeated
+	alerts, err = nwm.Check()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(alerts) != 0 {
+		t.Errorf("expected no repeated alerts, got %v", alerts)
+	}
+}
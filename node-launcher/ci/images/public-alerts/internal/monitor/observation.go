@@ -0,0 +1,195 @@
+package monitor
+
+import (
+	"fmt"
+	"public-alerts/internal/common"
+	"public-alerts/internal/config"
+	"public-alerts/internal/notify"
+	"sort"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+	openapi "gitlab.com/thorchain/thornode/openapi/gen"
+)
+
+// ObservationMonitor tracks, per active node and chain, whether the height observed
+// by the node's bifrost advances between polls. ChainLagMonitor only rates reported
+// heights against the tip, so a node that stops reporting a chain or repeats a stale
+// height goes unnoticed until it falls behind the limit. A poll only counts against
+// a node when the network's highest observed height for the chain advanced, so slow
+// chains are not mistaken for frozen ones. It alerts on nodes frozen for the
+// configured number of polls and on chains with too many frozen nodes.
+type ObservationMonitor struct {
+	client     common.ThornodeDataFetcher
+	networkMax map[string]int64                          // highest observed height at the last poll, by chain
+	observed   map[string]map[string]observedChainHeight // by node address and chain
+	tripped    map[string]bool                           // frozen nodes and chains already alerted
+}
+
+// observedChainHeight is the height a node observed for a chain and the polls it
+// has not advanced while the network did.
+type observedChainHeight struct {
+	height   int64
+	reported bool
+	frozen   int
+}
+
+func NewObservationMonitor(client common.ThornodeDataFetcher) *ObservationMonitor {
+	return &ObservationMonitor{
+		client:     client,
+		networkMax: make(map[string]int64),
+		observed:   make(map[string]map[string]observedChainHeight),
+		tripped:    make(map[string]bool),
+	}
+}
+
+func (om *ObservationMonitor) Name() string {
+	return "ObservationMonitor"
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// Helpers
+////////////////////////////////////////////////////////////////////////////////
+
+// networkObservedHeights returns the highest height active nodes observed per chain.
+func networkObservedHeights(nodes []openapi.Node) map[string]int64 {
+	heights := make(map[string]int64)
+	for _, node := range nodes {
+		if node.Status != "Active" {
+			continue
+		}
+		for _, c := range node.ObserveChains {
+			if c.GetHeight() > heights[c.Chain] {
+				heights[c.Chain] = c.GetHeight()
+			}
+		}
+	}
+	return heights
+}
+
+// advanceObservation returns the observation of a chain by a node this poll. A chain
+// the node no longer reports keeps its previous height.
+func advanceObservation(prev observedChainHeight, seen bool, height int64, reported, networkAdvanced bool) observedChainHeight {
+	current := observedChainHeight{height: height, reported: reported}
+	if !reported {
+		current.height = prev.height
+	}
+	switch {
+	case !seen || current.height > prev.height:
+		current.frozen = 0
+	case networkAdvanced:
+		current.frozen = prev.frozen + 1
+	default:
+		current.frozen = prev.frozen
+	}
+	return current
+}
+
+func describeFrozen(chain string, o observedChainHeight, networkHeight int64) string {
+	if !o.reported {
+		return fmt.Sprintf("> **%s:** not reported for %d polls (network at %d)", chain, o.frozen, networkHeight)
+	}
+	return fmt.Sprintf("> **%s:** stuck at %d for %d polls (network at %d)", chain, o.height, o.frozen, networkHeight)
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// Check
+////////////////////////////////////////////////////////////////////////////////
+
+func (om *ObservationMonitor) Check() ([]notify.Alert, error) {
+	log.Info().Msg("Checking bifrost observations...")
+	cfg := config.Get()
+	ocfg := cfg.ObservationMonitor
+
+	client, height, err := common.PinLatestHeight(om.client)
+	if err != nil {
+		return nil, err
+	}
+	nodes, err := client.GetNodes()
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].NodeAddress < nodes[j].NodeAddress })
+
+	networkMax := networkObservedHeights(nodes)
+	chains := make([]string, 0, len(networkMax))
+	for chain := range networkMax {
+		chains = append(chains, chain)
+	}
+	sort.Strings(chains)
+
+	var alerts []notify.Alert
+	conditions := make(map[string]bool)
+	frozenNodes := make(map[string]int)
+	active := 0
+	observed := make(map[string]map[string]observedChainHeight)
+	for _, node := range nodes {
+		if node.Status != "Active" {
+			continue
+		}
+		active++
+		reported := make(map[string]int64)
+		for _, c := range node.ObserveChains {
+			reported[c.Chain] = c.GetHeight()
+		}
+
+		var lines []string
+		observed[node.NodeAddress] = make(map[string]observedChainHeight)
+		for _, chain := range chains {
+			prev, seen := om.observed[node.NodeAddress][chain]
+			prevMax, known := om.networkMax[chain]
+			h, ok := reported[chain]
+			o := advanceObservation(prev, seen, h, ok, known && networkMax[chain] > prevMax)
+			observed[node.NodeAddress][chain] = o
+			if o.frozen < ocfg.FrozenPolls {
+				continue
+			}
+			frozenNodes[chain]++
+			key := "node/" + node.NodeAddress + "/" + chain
+			conditions[key] = true
+			if !om.tripped[key] {
+				om.tripped[key] = true
+				lines = append(lines, describeFrozen(chain, o, networkMax[chain]))
+			}
+		}
+		if len(lines) > 0 {
+			msg := fmt.Sprintf("### Node Observation Frozen: `%s`\n%s", common.ShortenAddress(node.NodeAddress), strings.Join(lines, "\n"))
+			alerts = append(alerts, notify.Alert{Webhooks: cfg.Webhooks.Activity, Message: msg, Height: height})
+		}
+	}
+	om.observed = observed
+	om.networkMax = networkMax
+
+	for _, chain := range chains {
+		key := "chain/" + chain
+		share := 0.0
+		if active > 0 {
+			share = float64(frozenNodes[chain]) / float64(active)
+		}
+		frozen := share > ocfg.MaxFrozenShare
+		if frozen {
+			conditions[key] = true
+		}
+		var title string
+		switch {
+		case frozen && !om.tripped[key]:
+			om.tripped[key] = true
+			title = "### Chain Observation Frozen: " + chain
+		case !frozen && om.tripped[key]:
+			delete(om.tripped, key)
+			title = "### Chain Observation Recovered: " + chain
+		default:
+			continue
+		}
+		msg := fmt.Sprintf("%s\n> **Frozen Nodes:** %d/%d (%s, limit %s)",
+			title, frozenNodes[chain], active, common.FormatPercent(share), common.FormatPercent(ocfg.MaxFrozenShare))
+		alerts = append(alerts, notify.Alert{Webhooks: cfg.Webhooks.Activity, Message: msg, Height: height})
+	}
+
+	for key := range om.tripped {
+		if !conditions[key] {
+			delete(om.tripped, key)
+		}
+	}
+	return alerts, nil
+}
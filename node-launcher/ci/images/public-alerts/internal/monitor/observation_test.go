@@ -0,0 +1,113 @@
+package monitor
+
+import (
+	"errors"
+	"public-alerts/internal/common"
+	"public-alerts/internal/notify"
+	"strings"
+	"testing"
+
+	openapi "gitlab.com/thorchain/thornode/openapi/gen"
+)
+
+func observingNode(address, status string, heights ...openapi.ChainHeight) openapi.Node {
+	return openapi.Node{NodeAddress: address, Status: status, ObserveChains: heights}
+}
+
+func TestAdvanceObservation(t *testing.T) {
+	prev := observedChainHeight{height: 100, reported: true, frozen: 2}
+	tests := []struct {
+		name            string
+		seen            bool
+		height          int64
+		reported        bool
+		networkAdvanced bool
+		expected        observedChainHeight
+	}{
+		{"first seen", false, 100, true, true, observedChainHeight{height: 100, reported: true}},
+		{"advanced", true, 101, true, true, observedChainHeight{height: 101, reported: true}},
+		{"frozen while network advanced", true, 100, true, true, observedChainHeight{height: 100, reported: true, frozen: 3}},
+		{"network did not advance", true, 100, true, false, observedChainHeight{height: 100, reported: true, frozen: 2}},
+		{"no longer reported", true, 0, false, true, observedChainHeight{height: 100, frozen: 3}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if o := advanceObservation(prev, tt.seen, tt.height, tt.reported, tt.networkAdvanced); o != tt.expected {
+				t.Errorf("expected %+v, got %+v", tt.expected, o)
+			}
+		})
+	}
+}
+
+func TestObservationMonitorCheck(t *testing.T) {
+	client := common.NewFakeClient()
+	client.Height = 1000
+	poll := func(btc, eth, frozenBTC int64, reportETH bool) {
+		client.Nodes = nil
+		for _, address := range []string{"thor1node1", "thor1node2", "thor1node3"} {
+			client.Nodes = append(client.Nodes, observingNode(address, "Active",
+				openapi.ChainHeight{Chain: "BTC", Height: btc}, openapi.ChainHeight{Chain: "ETH", Height: eth}))
+		}
+		frozen := observingNode("thor1nodefrozen", "Active", openapi.ChainHeight{Chain: "BTC", Height: frozenBTC})
+		if reportETH {
+			frozen.ObserveChains = append(frozen.ObserveChains, openapi.ChainHeight{Chain: "ETH", Height: eth})
+		}
+		client.Nodes = append(client.Nodes, frozen, observingNode("thor1standby", "Standby"))
+	}
+
+	om := NewObservationMonitor(client)
+	poll(100, 1000, 100, true)
+	if alerts, err := om.Check(); err != nil || len(alerts) != 0 {
+		t.Fatalf("expected no alerts on the first poll, got %v, %v", alerts, err)
+	}
+
+	// the frozen node stays on BTC 100 and stops reporting ETH, one poll the network
+	// does not advance either and it does not count
+	var alerts []notify.Alert
+	var err error
+	for i, btc := range []int64{101, 102, 102, 103, 104, 105} {
+		poll(btc, 1000+10*btc, 100, false)
+		if alerts, err = om.Check(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if i < 5 && len(alerts) != 0 {
+			t.Fatalf("expected no alerts before 5 frozen polls, got %v", alerts)
+		}
+	}
+	expected := []string{
+		strings.Join([]string{
+			"### Node Observation Frozen: `thor...ozen`",
+			"> **BTC:** stuck at 100 for 5 polls (network at 105)",
+			"> **ETH:** not reported for 5 polls (network at 2050)",
+		}, "\n"),
+		"### Chain Observation Frozen: BTC\n> **Frozen Nodes:** 1/4 (25.00%, limit 10.00%)",
+		"### Chain Observation Frozen: ETH\n> **Frozen Nodes:** 1/4 (25.00%, limit 10.00%)",
+	}
+	if len(alerts) != len(expected) {
+		t.Fatalf("expected %d alerts, got %v", len(expected), alerts)
+	}
+	for i, alert := range alerts {
+		if alert.Message != expected[i] {
+			t.Errorf("expected %q, got %q", expected[i], alert.Message)
+		}
+	}
+
+	// ongoing conditions are not repeated, BTC catching up recovers the chain
+	poll(106, 2060, 100, false)
+	if alerts, err = om.Check(); err != nil || len(alerts) != 0 {
+		t.Fatalf("expected no repeated alerts, got %v, %v", alerts, err)
+	}
+	poll(107, 2070, 107, false)
+	alerts, err = om.Check()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(alerts) != 1 || alerts[0].Message != "### Chain Observation Recovered: BTC\n> **Frozen Nodes:** 0/4 (0.00%, limit 10.00%)" {
+		t.Fatalf("expected a BTC recovery, got %v", alerts)
+	}
+
+	client.Err = errors.New("unavailable")
+	if _, err := om.Check(); err == nil {
+		t.Error("expected error from client")
+	}
+}
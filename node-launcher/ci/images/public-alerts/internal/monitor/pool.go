@@ -0,0 +1,229 @@
+package monitor
+
+import (
+	"fmt"
+	"public-alerts/internal/common"
+	"public-alerts/internal/config"
+	"public-alerts/internal/notify"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+	openapi "gitlab.com/thorchain/thornode/openapi/gen"
+)
+
+// PoolMonitor watches the pools for status transitions (Available, Staged,
+// Suspended), pools being added or removed, and sudden drops of the asset or RUNE
+// depth within the configured window, a common signature of an exploit draining
+// a pool. Drop alerts include the USD value of the depth lost.
+type PoolMonitor struct {
+	client      common.ThornodeDataFetcher
+	prices      common.PriceFetcher
+	initialized bool
+	status      map[string]string       // pool status at the last check
+	history     map[string][]poolSample // depth samples within the pool's window
+	tripped     map[string]bool         // "<pool>/<side>" drops already alerted
+}
+
+// poolSample is the depth of a pool at a height, in 1e8 units.
+type poolSample struct {
+	height int
+	asset  float64
+	rune   float64
+}
+
+func NewPoolMonitor(client common.ThornodeDataFetcher, prices common.PriceFetcher) *PoolMonitor {
+	return &PoolMonitor{
+		client:  client,
+		prices:  prices,
+		status:  make(map[string]string),
+		history: make(map[string][]poolSample),
+		tripped: make(map[string]bool),
+	}
+}
+
+func (pm *PoolMonitor) Name() string {
+	return "PoolMonitor"
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// Helpers
+////////////////////////////////////////////////////////////////////////////////
+
+func newPoolSample(pool openapi.Pool, height int) poolSample {
+	asset, _ := strconv.ParseFloat(pool.BalanceAsset, 64)
+	runeDepth, _ := strconv.ParseFloat(pool.BalanceRune, 64)
+	return poolSample{height: height, asset: asset, rune: runeDepth}
+}
+
+// assetTicker returns the ticker of an asset, e.g. USDC for ETH.USDC-0XA0B8...
+func assetTicker(asset string) string {
+	if _, symbol, ok := strings.Cut(asset, "."); ok {
+		asset = symbol
+	}
+	ticker, _, _ := strings.Cut(asset, "-")
+	return ticker
+}
+
+// poolStatusChanges describes the pools added, removed and changing status.
+func poolStatusChanges(prev map[string]string, pools []openapi.Pool) []string {
+	var msgs []string
+	current := make(map[string]bool, len(pools))
+	for _, pool := range pools {
+		current[pool.Asset] = true
+		old, ok := prev[pool.Asset]
+		switch {
+		case !ok:
+			msgs = append(msgs, fmt.Sprintf("### Pool Added: `%s`\n> **Status:** `%s`", pool.Asset, pool.Status))
+		case old != pool.Status:
+			msgs = append(msgs, fmt.Sprintf("### Pool Status Changed: `%s`\n> **Status:** `%s` → `%s`", pool.Asset, old, pool.Status))
+		}
+	}
+	var removed []string
+	for asset := range prev {
+		if !current[asset] {
+			removed = append(removed, asset)
+		}
+	}
+	sort.Strings(removed)
+	for _, asset := range removed {
+		msgs = append(msgs, fmt.Sprintf("### Pool Removed: `%s`\n> **Status:** `%s`", asset, prev[asset]))
+	}
+	return msgs
+}
+
+// depthDrop is a drop of one side of a pool from its peak within the window.
+type depthDrop struct {
+	side    string // "asset" or "rune"
+	from    float64
+	to      float64
+	blocks  int // blocks since the peak
+	percent float64
+}
+
+// depthDrops returns the drops of samples' latest depths from their peak that
+// exceed the threshold. samples are ordered by height and end with the latest.
+func depthDrops(samples []poolSample, threshold config.PoolDepthThreshold) []depthDrop {
+	if len(samples) < 2 {
+		return nil
+	}
+	latest := samples[len(samples)-1]
+	var drops []depthDrop
+	for _, side := range []string{"asset", "rune"} {
+		depth := func(s poolSample) float64 {
+			if side == "asset" {
+				return s.asset
+			}
+			return s.rune
+		}
+		peak := latest
+		for _, s := range samples {
+			if depth(s) > depth(peak) {
+				peak = s
+			}
+		}
+		if depth(peak) == 0 {
+			continue
+		}
+		percent := (depth(peak) - depth(latest)) / depth(peak)
+		if percent > threshold.MaxDropPercent {
+			drops = append(drops, depthDrop{side: side, from: depth(peak), to: depth(latest), blocks: latest.height - peak.height, percent: percent})
+		}
+	}
+	return drops
+}
+
+// describeDepthDrop describes a drop of pool, valuing it at the USD prices if known.
+func describeDepthDrop(pool string, drop depthDrop, threshold config.PoolDepthThreshold, prices map[string]float64) string {
+	label, priced, unit := "Asset Depth", pool, assetTicker(pool)
+	if drop.side == "rune" {
+		label, priced, unit = "RUNE Depth", common.RuneAsset, "RUNE"
+	}
+	msg := fmt.Sprintf("> **%s:** %.2f → %.2f %s (-%s) within %d blocks (limit %s)",
+		label, drop.from/1e8, drop.to/1e8, unit, common.FormatPercent(drop.percent), drop.blocks, common.FormatPercent(threshold.MaxDropPercent))
+	if price, ok := prices[priced]; ok {
+		msg += fmt.Sprintf("\n> **Value Lost:** %s", common.FormatUSD((drop.from-drop.to)/1e8*price))
+	}
+	return msg
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// Check
+////////////////////////////////////////////////////////////////////////////////
+
+func (pm *PoolMonitor) Check() ([]notify.Alert, error) {
+	log.Info().Msg("Checking pools...")
+	cfg := config.Get()
+
+	client, height, err := common.PinLatestHeight(pm.client)
+	if err != nil {
+		return nil, err
+	}
+	pools, err := client.GetPools()
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(pools, func(i, j int) bool { return pools[i].Asset < pools[j].Asset })
+
+	var msgs []string
+	if pm.initialized {
+		msgs = poolStatusChanges(pm.status, pools)
+	}
+	pm.initialized = true
+	pm.status = make(map[string]string, len(pools))
+	current := make(map[string]bool, len(pools))
+
+	var prices map[string]float64
+	for _, pool := range pools {
+		pm.status[pool.Asset] = pool.Status
+		current[pool.Asset] = true
+
+		// keep the samples within the pool's window
+		threshold := cfg.PoolMonitor.DepthThreshold(pool.Asset)
+		samples := append(pm.history[pool.Asset], newPoolSample(pool, height))
+		for len(samples) > 0 && samples[0].height < height-threshold.WindowBlocks {
+			samples = samples[1:]
+		}
+		pm.history[pool.Asset] = samples
+
+		drops := depthDrops(samples, threshold)
+		var lines []string
+		dropped := make(map[string]bool)
+		for _, drop := range drops {
+			key := pool.Asset + "/" + drop.side
+			dropped[key] = true
+			if pm.tripped[key] {
+				continue
+			}
+			pm.tripped[key] = true
+			// prices are only needed, and only fetched, once a drop is found
+			if prices == nil {
+				if prices, err = pm.prices.GetAssetPricesUSD(); err != nil {
+					log.Warn().Err(err).Msg("failed to fetch prices for pool depth drop")
+					prices = map[string]float64{}
+				}
+			}
+			lines = append(lines, describeDepthDrop(pool.Asset, drop, threshold, prices))
+		}
+		for _, side := range []string{"asset", "rune"} {
+			if key := pool.Asset + "/" + side; !dropped[key] {
+				delete(pm.tripped, key)
+			}
+		}
+		if len(lines) > 0 {
+			msgs = append(msgs, fmt.Sprintf("### Pool Depth Drop: `%s`\n%s", pool.Asset, strings.Join(lines, "\n")))
+		}
+	}
+	for asset := range pm.history {
+		if !current[asset] {
+			delete(pm.history, asset)
+		}
+	}
+
+	var alerts []notify.Alert
+	for _, msg := range msgs {
+		alerts = append(alerts, notify.Alert{Webhooks: cfg.Webhooks.Activity, Message: msg, Height: height})
+	}
+	return alerts, nil
+}
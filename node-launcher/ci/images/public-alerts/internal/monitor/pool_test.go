@@ -0,0 +1,139 @@
+package monitor
+
+import (
+	"public-alerts/internal/common"
+	"public-alerts/internal/config"
+	"strings"
+	"testing"
+
+	openapi "gitlab.com/thorchain/thornode/openapi/gen"
+)
+
+func TestAssetTicker(t *testing.T) {
+	for asset, want := range map[string]string{
+		"BTC.BTC": "BTC",
+		"ETH.USDC-0XA0B86991C6218B36C1D19D4A2E9EB0CE3606EB48": "USDC",
+		"THOR.RUNE": "RUNE",
+	} {
+		if got := assetTicker(asset); got != want {
+			t.Errorf("assetTicker(%s) = %s, want %s", asset, got, want)
+		}
+	}
+}
+
+func TestPoolStatusChanges(t *testing.T) {
+	prev := map[string]string{"BTC.BTC": "Available", "ETH.ETH": "Available", "BNB.BNB": "Available"}
+	pools := []openapi.Pool{
+		{Asset: "AVAX.AVAX", Status: "Staged"},
+		{Asset: "BTC.BTC", Status: "Available"},
+		{Asset: "ETH.ETH", Status: "Suspended"},
+	}
+	msgs := poolStatusChanges(prev, pools)
+	expected := []string{
+		"### Pool Added: `AVAX.AVAX`\n> **Status:** `Staged`",
+		"### Pool Status Changed: `ETH.ETH`\n> **Status:** `Available` → `Suspended`",
+		"### Pool Removed: `BNB.BNB`\n> **Status:** `Available`",
+	}
+	if strings.Join(msgs, "|") != strings.Join(expected, "|") {
+		t.Errorf("expected %q, got %q", expected, msgs)
+	}
+}
+
+func TestDepthDrops(t *testing.T) {
+	threshold := config.PoolDepthThreshold{MaxDropPercent: 0.1, WindowBlocks: 300}
+	tests := []struct {
+		name    string
+		samples []poolSample
+		want    []string
+	}{
+		{
+			name:    "single sample",
+			samples: []poolSample{{height: 100, asset: 100, rune: 100}},
+		},
+		{
+			name:    "within threshold",
+			samples: []poolSample{{height: 100, asset: 100, rune: 100}, {height: 110, asset: 95, rune: 105}},
+		},
+		{
+			name:    "asset drained from peak",
+			samples: []poolSample{{height: 100, asset: 100, rune: 100}, {height: 110, asset: 120, rune: 100}, {height: 120, asset: 60, rune: 100}},
+			want:    []string{"asset"},
+		},
+		{
+			name:    "both sides withdrawn",
+			samples: []poolSample{{height: 100, asset: 100, rune: 100}, {height: 110, asset: 80, rune: 80}},
+			want:    []string{"asset", "rune"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			drops := depthDrops(tt.samples, threshold)
+			var sides []string
+			for _, drop := range drops {
+				sides = append(sides, drop.side)
+			}
+			if strings.Join(sides, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("expected %v, got %+v", tt.want, drops)
+			}
+		})
+	}
+}
+
+func TestPoolMonitorCheck(t *testing.T) {
+	client := common.NewFakeClient()
+	client.Height = 1000
+	client.Pools = []openapi.Pool{
+		{Asset: "BTC.BTC", Status: "Available", BalanceAsset: "100000000000", BalanceRune: "5000000000000000"},
+		{Asset: "DOGE.DOGE", Status: "Available", BalanceAsset: "100000000000", BalanceRune: "10000000000"},
+	}
+	client.Prices = map[string]float64{"BTC.BTC": 60000, common.RuneAsset: 1.2}
+
+	pm := NewPoolMonitor(client, client)
+	alerts, err := pm.Check()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(alerts) != 0 {
+		t.Fatalf("expected the first check to only record state, got %v", alerts)
+	}
+
+	// BTC is drained by 20% and DOGE is staged
+	client.Height = 1010
+	client.Pools = []openapi.Pool{
+		{Asset: "BTC.BTC", Status: "Available", BalanceAsset: "80000000000", BalanceRune: "5000000000000000"},
+		{Asset: "DOGE.DOGE", Status: "Staged", BalanceAsset: "100000000000", BalanceRune: "10000000000"},
+	}
+	alerts, err = pm.Check()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(alerts) != 2 {
+		t.Fatalf("expected status and depth drop alerts, got %v", alerts)
+	}
+	if !strings.HasPrefix(alerts[0].Message, "### Pool Status Changed: `DOGE.DOGE`") {
+		t.Errorf("expected DOGE status change first, got %q", alerts[0].Message)
+	}
+	expected := "### Pool Depth Drop: `BTC.BTC`\n> **Asset Depth:** 1000.00 → 800.00 BTC (-20.00%) within 10 blocks (limit 5.00%)\n> **Value Lost:** $12000000"
+	if alerts[1].Message != expected || alerts[1].Height != 1010 {
+		t.Errorf("expected %q at 1010, got %q at %d", expected, alerts[1].Message, alerts[1].Height)
+	}
+
+	// the ongoing drop is not repeated
+	client.Height = 1020
+	alerts, err = pm.Check()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(alerts) != 0 {
+		t.Fatalf("expected no repeated alerts, got %v", alerts)
+	}
+
+	// once the peak leaves the window the drop clears
+	client.Height = 1400
+	if alerts, err = pm.Check(); err != nil || len(alerts) != 0 {
+		t.Fatalf("expected no alerts, got %v, %v", alerts, err)
+	}
+	if len(pm.tripped) != 0 {
+		t.Errorf("expected the drop to clear, got %v", pm.tripped)
+	}
+}
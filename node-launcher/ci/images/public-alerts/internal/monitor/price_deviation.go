@@ -0,0 +1,185 @@
+package monitor
+
+import (
+	"fmt"
+	"math"
+	"public-alerts/internal/common"
+	"public-alerts/internal/config"
+	"public-alerts/internal/notify"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	openapi "gitlab.com/thorchain/thornode/openapi/gen"
+)
+
+// PriceDeviationMonitor compares the prices implied by pool depths. It alerts when
+// the RUNE price implied by a stable pool deviates from the median of the stable
+// pools, when the price of an asset pooled on several chains (e.g. ETH.USDT and
+// AVAX.USDT) deviates from its cross-chain median, and, with a reference feed
+// configured, when a pool price deviates from the reference. Deviations must last
+// for the sustained period before they are alerted, as arbitrage usually closes
+// them within a few blocks.
+type PriceDeviationMonitor struct {
+	client    common.ThornodeDataFetcher
+	reference common.PriceFetcher // optional
+	now       func() time.Time
+	since     map[string]time.Time // when each ongoing deviation was first seen
+	tripped   map[string]bool      // deviations already alerted
+}
+
+func NewPriceDeviationMonitor(client common.ThornodeDataFetcher, reference common.PriceFetcher) *PriceDeviationMonitor {
+	return &PriceDeviationMonitor{
+		client:    client,
+		reference: reference,
+		now:       time.Now,
+		since:     make(map[string]time.Time),
+		tripped:   make(map[string]bool),
+	}
+}
+
+func (pdm *PriceDeviationMonitor) Name() string {
+	return "PriceDeviationMonitor"
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// Helpers
+////////////////////////////////////////////////////////////////////////////////
+
+func deviation(price, reference float64) float64 {
+	return math.Abs(price-reference) / reference
+}
+
+// priceDeviations returns the current price deviations above maxDeviation keyed by
+// "<kind>/<asset>". reference prices are compared against when non-nil.
+func priceDeviations(pools []openapi.Pool, stablePools []string, reference map[string]float64, maxDeviation float64) map[string]string {
+	deviations := make(map[string]string)
+	runePrices := common.PoolRunePrices(pools)
+
+	// RUNE price implied by each stable pool against their median
+	implied := make(map[string]float64)
+	var impliedPrices []float64
+	for _, asset := range stablePools {
+		if price, ok := runePrices[asset]; ok {
+			implied[asset] = 1 / price
+			impliedPrices = append(impliedPrices, 1/price)
+		}
+	}
+	if len(implied) > 1 {
+		mid := median(impliedPrices)
+		for asset, price := range implied {
+			if d := deviation(price, mid); d > maxDeviation {
+				deviations["rune/"+asset] = fmt.Sprintf("> **RUNE in `%s`:** $%.4f, %s from the stable pool median $%.4f",
+					asset, price, common.FormatPercent(d), mid)
+			}
+		}
+	}
+
+	// assets pooled on several chains against their cross-chain median
+	groups := make(map[string][]string)
+	for asset := range runePrices {
+		ticker := assetTicker(asset)
+		groups[ticker] = append(groups[ticker], asset)
+	}
+	for ticker, assets := range groups {
+		if len(assets) < 2 {
+			continue
+		}
+		prices := make([]float64, 0, len(assets))
+		for _, asset := range assets {
+			prices = append(prices, runePrices[asset])
+		}
+		mid := median(prices)
+		for _, asset := range assets {
+			if d := deviation(runePrices[asset], mid); d > maxDeviation {
+				deviations["chain/"+asset] = fmt.Sprintf("> **`%s`:** %.6f RUNE, %s from the %s median %.6f RUNE",
+					asset, runePrices[asset], common.FormatPercent(d), ticker, mid)
+			}
+		}
+	}
+
+	// pool prices against the reference feed
+	if reference != nil {
+		if prices, err := common.PoolPricesUSD(pools, stablePools); err == nil {
+			for asset, price := range prices {
+				ref, ok := reference[asset]
+				if !ok || ref <= 0 {
+					continue
+				}
+				if d := deviation(price, ref); d > maxDeviation {
+					deviations["reference/"+asset] = fmt.Sprintf("> **`%s`:** $%.4f, %s from the reference price $%.4f",
+						asset, price, common.FormatPercent(d), ref)
+				}
+			}
+		}
+	}
+	return deviations
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// Check
+////////////////////////////////////////////////////////////////////////////////
+
+func (pdm *PriceDeviationMonitor) Check() ([]notify.Alert, error) {
+	log.Info().Msg("Checking pool price deviations...")
+	cfg := config.Get()
+
+	client, height, err := common.PinLatestHeight(pdm.client)
+	if err != nil {
+		return nil, err
+	}
+	pools, err := client.GetPools()
+	if err != nil {
+		return nil, err
+	}
+	var reference map[string]float64
+	referenceDown := false
+	if pdm.reference != nil {
+		// pool deviations are still checked while the reference is unavailable
+		if reference, err = pdm.reference.GetAssetPricesUSD(); err != nil {
+			log.Warn().Err(err).Msg("failed to fetch reference prices")
+			referenceDown = true
+		}
+	}
+	deviations := priceDeviations(pools, cfg.Pricing.StablePools, reference, cfg.PriceDeviationMonitor.MaxDeviation)
+
+	now := pdm.now()
+	var deviating, resolved []string
+	for key, line := range deviations {
+		since, ok := pdm.since[key]
+		if !ok {
+			pdm.since[key] = now
+			since = now
+		}
+		if !pdm.tripped[key] && now.Sub(since) >= cfg.PriceDeviationMonitor.SustainedPeriod {
+			pdm.tripped[key] = true
+			deviating = append(deviating, line)
+		}
+	}
+	for key := range pdm.since {
+		if _, ok := deviations[key]; ok || (referenceDown && strings.HasPrefix(key, "reference/")) {
+			continue
+		}
+		delete(pdm.since, key)
+		if pdm.tripped[key] {
+			delete(pdm.tripped, key)
+			_, asset, _ := strings.Cut(key, "/")
+			resolved = append(resolved, fmt.Sprintf("> `%s`", asset))
+		}
+	}
+
+	var alerts []notify.Alert
+	if len(deviating) > 0 {
+		sort.Strings(deviating)
+		msg := fmt.Sprintf("### Pool Price Deviation\n> **Sustained:** %s (limit %s)\n%s",
+			cfg.PriceDeviationMonitor.SustainedPeriod, common.FormatPercent(cfg.PriceDeviationMonitor.MaxDeviation), strings.Join(deviating, "\n"))
+		alerts = append(alerts, notify.Alert{Webhooks: cfg.Webhooks.Activity, Message: msg, Height: height})
+	}
+	if len(resolved) > 0 {
+		sort.Strings(resolved)
+		msg := "### Pool Price Deviation Resolved\n" + strings.Join(resolved, "\n")
+		alerts = append(alerts, notify.Alert{Webhooks: cfg.Webhooks.Activity, Message: msg, Height: height})
+	}
+	return alerts, nil
+}
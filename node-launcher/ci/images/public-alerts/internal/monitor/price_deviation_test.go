@@ -0,0 +1,122 @@
+package monitor
+
+import (
+	"errors"
+	"public-alerts/internal/common"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	openapi "gitlab.com/thorchain/thornode/openapi/gen"
+)
+
+func depthPool(asset, assetDepth, runeDepth string) openapi.Pool {
+	return openapi.Pool{Asset: asset, Status: "Available", BalanceAsset: assetDepth, BalanceRune: runeDepth}
+}
+
+func TestPriceDeviations(t *testing.T) {
+	stable := []string{"ETH.USDC", "ETH.USDT", "AVAX.USDC"}
+	pools := []openapi.Pool{
+		depthPool("ETH.USDC", "500000000", "100000000"),  // RUNE = $5.00
+		depthPool("AVAX.USDC", "505000000", "100000000"), // RUNE = $5.05
+		depthPool("ETH.USDT", "600000000", "100000000"),  // RUNE = $6.00, depegged
+		depthPool("BTC.BTC", "100000000", "2000000000"),
+	}
+
+	tests := []struct {
+		name      string
+		reference map[string]float64
+		want      []string
+	}{
+		{
+			name: "pool deviations",
+			want: []string{"rune/ETH.USDT"},
+		},
+		{
+			name:      "reference deviation",
+			reference: map[string]float64{"BTC.BTC": 80, "THOR.RUNE": 5.05},
+			want:      []string{"reference/BTC.BTC", "rune/ETH.USDT"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			deviations := priceDeviations(pools, stable, tt.reference, 0.02)
+			var keys []string
+			for key := range deviations {
+				keys = append(keys, key)
+			}
+			sort.Strings(keys)
+			if strings.Join(keys, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("expected %v, got %v", tt.want, deviations)
+			}
+		})
+	}
+
+	// the same asset on two chains
+	pools = append(pools, depthPool("BSC.USDT", "600000000", "110000000"))
+	deviations := priceDeviations(pools, nil, nil, 0.02)
+	if len(deviations) != 2 || deviations["chain/BSC.USDT"] == "" || deviations["chain/ETH.USDT"] == "" {
+		t.Errorf("expected both USDT pools to deviate, got %v", deviations)
+	}
+}
+
+func TestPriceDeviationMonitorCheck(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	client := common.NewFakeClient()
+	client.Height = 100
+	client.Pools = []openapi.Pool{
+		depthPool("ETH.USDC-0XA0B86991C6218B36C1D19D4A2E9EB0CE3606EB48", "500000000", "100000000"),
+		depthPool("ETH.USDT-0XDAC17F958D2EE523A2206206994597C13D831EC7", "500000000", "100000000"),
+		depthPool("BSC.USDT-0X55D398326F99059FF775485246999027B3197955", "500000000", "100000000"),
+	}
+	reference := common.NewFakeClient()
+	reference.Prices = map[string]float64{"THOR.RUNE": 5}
+
+	pdm := NewPriceDeviationMonitor(client, reference)
+	pdm.now = func() time.Time { return now }
+	check := func() []string {
+		t.Helper()
+		alerts, err := pdm.Check()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		var msgs []string
+		for _, alert := range alerts {
+			msgs = append(msgs, alert.Message)
+		}
+		return msgs
+	}
+
+	if msgs := check(); len(msgs) != 0 {
+		t.Fatalf("expected no deviations, got %v", msgs)
+	}
+
+	// the RUNE reference diverges, but not for long enough yet
+	reference.Prices["THOR.RUNE"] = 6
+	if msgs := check(); len(msgs) != 0 {
+		t.Fatalf("expected the deviation to wait for the sustained period, got %v", msgs)
+	}
+
+	now = now.Add(10 * time.Minute)
+	msgs := check()
+	if len(msgs) != 1 || !strings.Contains(msgs[0], "**`THOR.RUNE`:** $5.0000, 16.67% from the reference price $6.0000") {
+		t.Fatalf("expected a sustained reference deviation, got %v", msgs)
+	}
+	if msgs := check(); len(msgs) != 0 {
+		t.Fatalf("expected the deviation not to be repeated, got %v", msgs)
+	}
+
+	// the reference feed failing doesn't resolve the deviation
+	reference.Err = errors.New("unavailable")
+	if msgs := check(); len(msgs) != 0 {
+		t.Fatalf("expected no alerts while the reference is unavailable, got %v", msgs)
+	}
+
+	reference.Err = nil
+	reference.Prices["THOR.RUNE"] = 5
+	msgs = check()
+	if len(msgs) != 1 || !strings.HasPrefix(msgs[0], "### Pool Price Deviation Resolved\n> `THOR.RUNE`") {
+		t.Fatalf("expected the deviation to resolve, got %v", msgs)
+	}
+}
@@ -0,0 +1,254 @@
+package monitor
+
+import (
+	"fmt"
+	"math"
+	"public-alerts/internal/common"
+	"public-alerts/internal/config"
+	"public-alerts/internal/notify"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+)
+
+// ProviderConsistencyMonitor queries every configured THORNode provider for the same
+// data and alerts when they disagree beyond the configured tolerances.
+type ProviderConsistencyMonitor struct {
+	providers []common.ProviderClient
+	tripped   map[string]bool // disagreement kinds that have already been alerted
+}
+
+func NewProviderConsistencyMonitor(providers []common.ProviderClient) *ProviderConsistencyMonitor {
+	return &ProviderConsistencyMonitor{
+		providers: providers,
+		tripped:   make(map[string]bool),
+	}
+}
+
+func (pcm *ProviderConsistencyMonitor) Name() string {
+	return "ProviderConsistencyMonitor"
+}
+
+// providerSnapshot is the data a single provider reported during a check.
+type providerSnapshot struct {
+	name        string
+	height      int
+	activeNodes map[string]bool
+	balances    map[string]float64 // total asgard vault balance per asset
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// Helpers
+////////////////////////////////////////////////////////////////////////////////
+
+// fetchProviderSnapshot reads the node list and vaults from provider at the given
+// height, so that providers at different tips are compared on the same block.
+func fetchProviderSnapshot(provider common.ProviderClient, latest, height int) (providerSnapshot, error) {
+	snapshot := providerSnapshot{
+		name:        provider.Name,
+		height:      latest,
+		activeNodes: make(map[string]bool),
+		balances:    make(map[string]float64),
+	}
+	client := provider.AtHeight(height)
+
+	nodes, err := client.GetNodes()
+	if err != nil {
+		return snapshot, err
+	}
+	for _, node := range nodes {
+		if node.Status == "Active" {
+			snapshot.activeNodes[node.NodeAddress] = true
+		}
+	}
+
+	vaults, err := client.GetVaults()
+	if err != nil {
+		return snapshot, err
+	}
+	for _, vault := range vaults {
+		for _, coin := range vault.Coins {
+			amount, err := strconv.ParseFloat(coin.Amount, 64)
+			if err != nil {
+				continue
+			}
+			snapshot.balances[coin.Asset] += amount
+		}
+	}
+
+	return snapshot, nil
+}
+
+func median(values []float64) float64 {
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[mid-1] + sorted[mid]) / 2
+	}
+	return sorted[mid]
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// Compare Providers
+////////////////////////////////////////////////////////////////////////////////
+
+// compareHeights reports when the spread of provider heights exceeds the tolerance.
+func compareHeights(snapshots []providerSnapshot, tolerance int) string {
+	minHeight, maxHeight := snapshots[0].height, snapshots[0].height
+	var heights []string
+	for _, s := range snapshots {
+		if s.height < minHeight {
+			minHeight = s.height
+		}
+		if s.height > maxHeight {
+			maxHeight = s.height
+		}
+		heights = append(heights, fmt.Sprintf("%s=%d", s.name, s.height))
+	}
+	if maxHeight-minHeight <= tolerance {
+		return ""
+	}
+	return fmt.Sprintf("[height] providers disagree by %d blocks: %s", maxHeight-minHeight, strings.Join(heights, ", "))
+}
+
+// compareActiveNodes reports providers whose active node set differs from the majority
+// view by more than the tolerance.
+func compareActiveNodes(snapshots []providerSnapshot, tolerance int) string {
+	votes := make(map[string]int)
+	for _, s := range snapshots {
+		for node := range s.activeNodes {
+			votes[node]++
+		}
+	}
+
+	var diffs []string
+	for _, s := range snapshots {
+		extra, missing := 0, 0
+		for node, count := range votes {
+			inMajority := count*2 > len(snapshots)
+			if s.activeNodes[node] && !inMajority {
+				extra++
+			} else if !s.activeNodes[node] && inMajority {
+				missing++
+			}
+		}
+		if extra+missing > tolerance {
+			diffs = append(diffs, fmt.Sprintf("%s (+%d/-%d)", s.name, extra, missing))
+		}
+	}
+	if len(diffs) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("[nodes] active node set differs from majority: %s", strings.Join(diffs, ", "))
+}
+
+// compareVaultBalances reports assets where a provider's total vault balance deviates
+// from the median of all providers by more than the relative tolerance.
+func compareVaultBalances(snapshots []providerSnapshot, tolerance float64) string {
+	assets := make(map[string]bool)
+	for _, s := range snapshots {
+		for asset := range s.balances {
+			assets[asset] = true
+		}
+	}
+	sortedAssets := make([]string, 0, len(assets))
+	for asset := range assets {
+		sortedAssets = append(sortedAssets, asset)
+	}
+	sort.Strings(sortedAssets)
+
+	var diffs []string
+	for _, asset := range sortedAssets {
+		values := make([]float64, 0, len(snapshots))
+		for _, s := range snapshots {
+			values = append(values, s.balances[asset])
+		}
+		mid := median(values)
+
+		var deviating []string
+		for _, s := range snapshots {
+			deviation := math.Abs(s.balances[asset] - mid)
+			if mid != 0 {
+				deviation /= mid
+			}
+			if deviation > tolerance {
+				deviating = append(deviating, fmt.Sprintf("%s=%.0f", s.name, s.balances[asset]))
+			}
+		}
+		if len(deviating) > 0 {
+			diffs = append(diffs, fmt.Sprintf("%s (median %.0f: %s)", asset, mid, strings.Join(deviating, ", ")))
+		}
+	}
+	if len(diffs) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("[vaults] vault balances disagree: %s", strings.Join(diffs, "; "))
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// Check
+////////////////////////////////////////////////////////////////////////////////
+
+func (pcm *ProviderConsistencyMonitor) Check() ([]notify.Alert, error) {
+	log.Info().Msg("Checking provider consistency...")
+	cfg := config.Get()
+
+	// unavailable providers are handled by failover, only compare those that respond
+	var responding []common.ProviderClient
+	var latest []int
+	pinHeight := 0
+	for _, provider := range pcm.providers {
+		height, err := provider.GetLatestHeight()
+		if err != nil {
+			log.Warn().Err(err).Str("provider", provider.Name).Msg("skipping provider in consistency check")
+			continue
+		}
+		responding = append(responding, provider)
+		latest = append(latest, height)
+		if pinHeight == 0 || height < pinHeight {
+			pinHeight = height
+		}
+	}
+
+	var snapshots []providerSnapshot
+	for i, provider := range responding {
+		snapshot, err := fetchProviderSnapshot(provider, latest[i], pinHeight)
+		if err != nil {
+			log.Warn().Err(err).Str("provider", provider.Name).Msg("skipping provider in consistency check")
+			continue
+		}
+		snapshots = append(snapshots, snapshot)
+	}
+	if len(snapshots) < 2 {
+		log.Warn().Msgf("only %d of %d providers responded, skipping consistency check", len(snapshots), len(pcm.providers))
+		return nil, nil
+	}
+
+	findings := map[string]string{
+		"height":   compareHeights(snapshots, cfg.ProviderConsistencyMonitor.HeightTolerance),
+		"nodes":    compareActiveNodes(snapshots, cfg.ProviderConsistencyMonitor.NodeTolerance),
+		"balances": compareVaultBalances(snapshots, cfg.ProviderConsistencyMonitor.BalanceTolerance),
+	}
+
+	var msgs []string
+	for _, kind := range []string{"height", "nodes", "balances"} {
+		finding := findings[kind]
+		if finding == "" {
+			delete(pcm.tripped, kind)
+			continue
+		}
+		if !pcm.tripped[kind] {
+			msgs = append(msgs, finding)
+			pcm.tripped[kind] = true
+		}
+	}
+
+	if len(msgs) > 0 {
+		msg := "```" + strings.Join(msgs, "\n") + "```"
+		return []notify.Alert{{Webhooks: cfg.Webhooks.Errors, Message: msg, Height: pinHeight}}, nil
+	}
+	return nil, nil
+}
@@ -0,0 +1,100 @@
+package monitor
+
+import (
+	"errors"
+	"public-alerts/internal/common"
+	"strings"
+	"testing"
+
+	openapi "gitlab.com/thorchain/thornode/openapi/gen"
+)
+
+func newConsistentProvider(name string) (common.ProviderClient, *common.FakeClient) {
+	client := common.NewFakeClient()
+	client.Height = 1000
+	client.Nodes = []openapi.Node{
+		{NodeAddress: "thor1a", Status: "Active"},
+		{NodeAddress: "thor1b", Status: "Active"},
+		{NodeAddress: "thor1c", Status: "Standby"},
+	}
+	client.Vaults = []openapi.Vault{{
+		Status: "ActiveVault",
+		Coins:  []openapi.Coin{{Asset: "BTC.BTC", Amount: "100000000"}, {Asset: "ETH.ETH", Amount: "500000000"}},
+	}}
+	return common.ProviderClient{Name: name, ThornodeDataFetcher: client}, client
+}
+
+func TestCompareProviderSnapshots(t *testing.T) {
+	snapshots := []providerSnapshot{
+		{name: "a", height: 100, activeNodes: map[string]bool{"n1": true, "n2": true}, balances: map[string]float64{"BTC.BTC": 1000}},
+		{name: "b", height: 102, activeNodes: map[string]bool{"n1": true, "n2": true}, balances: map[string]float64{"BTC.BTC": 1000}},
+		{name: "c", height: 110, activeNodes: map[string]bool{"n1": true, "n3": true}, balances: map[string]float64{"BTC.BTC": 900}},
+	}
+
+	if msg := compareHeights(snapshots, 10); msg != "" {
+		t.Errorf("expected heights within tolerance, got %q", msg)
+	}
+	if msg := compareHeights(snapshots, 5); !strings.Contains(msg, "disagree by 10 blocks") {
+		t.Errorf("unexpected height finding: %q", msg)
+	}
+
+	if msg := compareActiveNodes(snapshots, 0); msg != "[nodes] active node set differs from majority: c (+1/-1)" {
+		t.Errorf("unexpected node finding: %q", msg)
+	}
+	if msg := compareActiveNodes(snapshots, 2); msg != "" {
+		t.Errorf("expected node sets within tolerance, got %q", msg)
+	}
+
+	if msg := compareVaultBalances(snapshots, 0.01); msg != "[vaults] vault balances disagree: BTC.BTC (median 1000: c=900)" {
+		t.Errorf("unexpected balance finding: %q", msg)
+	}
+	if msg := compareVaultBalances(snapshots, 0.2); msg != "" {
+		t.Errorf("expected balances within tolerance, got %q", msg)
+	}
+}
+
+func TestProviderConsistencyMonitorCheck(t *testing.T) {
+	p1, _ := newConsistentProvider("ninerealms")
+	p2, _ := newConsistentProvider("liquify")
+	p3, bad := newConsistentProvider("local")
+
+	pcm := NewProviderConsistencyMonitor([]common.ProviderClient{p1, p2, p3})
+
+	alerts, err := pcm.Check()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(alerts) != 0 {
+		t.Fatalf("expected no alerts for consistent providers, got %v", alerts)
+	}
+
+	// one provider reports a stale height and a different vault balance
+	bad.Height = 900
+	bad.Vaults[0].Coins[0].Amount = "50000000"
+	alerts, err = pcm.Check()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(alerts) != 1 {
+		t.Fatalf("expected 1 alert, got %d", len(alerts))
+	}
+	for _, want := range []string{"[height]", "local=900", "[vaults]", "BTC.BTC"} {
+		if !strings.Contains(alerts[0].Message, want) {
+			t.Errorf("expected alert to contain %q, got %s", want, alerts[0].Message)
+		}
+	}
+	if strings.Contains(alerts[0].Message, "[nodes]") {
+		t.Errorf("unexpected node disagreement: %s", alerts[0].Message)
+	}
+
+	// the same disagreement is only reported once
+	if alerts, _ = pcm.Check(); len(alerts) != 0 {
+		t.Errorf("expected no repeated alerts, got %d", len(alerts))
+	}
+
+	// unavailable providers are skipped rather than reported as disagreeing
+	bad.Err = errors.New("unavailable")
+	if alerts, err = pcm.Check(); err != nil || len(alerts) != 0 {
+		t.Errorf("expected no alerts or errors with a provider down, got %v, %v", alerts, err)
+	}
+}
@@ -0,0 +1,213 @@
+package monitor
+
+import (
+	"fmt"
+	"public-alerts/internal/common"
+	"public-alerts/internal/config"
+	"public-alerts/internal/notify"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+	openapi "gitlab.com/thorchain/thornode/openapi/gen"
+)
+
+// QueueMonitor watches the aggregate health of the outbound and scheduled queues,
+// complementing the per-item age checks of OutboundMonitor. It alerts when the
+// queued items or their USD value grow past the configured limits, and when one
+// chain's queue keeps growing while the other chains drain, which usually means
+// a signer or gas problem on that chain.
+type QueueMonitor struct {
+	client  common.ThornodeDataFetcher
+	prices  common.PriceFetcher
+	history []queueSnapshot // snapshots within the growth window, oldest first
+	tripped map[string]bool // conditions already alerted
+}
+
+// queueTotals are the queued items and their USD value.
+type queueTotals struct {
+	Items int
+	USD   float64
+}
+
+// queueSnapshot is the queue backlog at a height by chain.
+type queueSnapshot struct {
+	height  int
+	byChain map[string]queueTotals
+}
+
+func NewQueueMonitor(client common.ThornodeDataFetcher, prices common.PriceFetcher) *QueueMonitor {
+	return &QueueMonitor{
+		client:  client,
+		prices:  prices,
+		tripped: make(map[string]bool),
+	}
+}
+
+func (qm *QueueMonitor) Name() string {
+	return "QueueMonitor"
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// Helpers
+////////////////////////////////////////////////////////////////////////////////
+
+// queueBacklog totals the queued items by chain and by vault.
+func queueBacklog(items []openapi.TxOutItem, prices map[string]float64) (byChain, byVault map[string]queueTotals) {
+	byChain, byVault = make(map[string]queueTotals), make(map[string]queueTotals)
+	for _, item := range items {
+		amount, _ := strconv.ParseFloat(item.Coin.Amount, 64)
+		usd := amount / 1e8 * prices[item.Coin.Asset]
+
+		chain := byChain[item.Chain]
+		chain.Items++
+		chain.USD += usd
+		byChain[item.Chain] = chain
+
+		vault := byVault[item.GetVaultPubKey()]
+		vault.Items++
+		vault.USD += usd
+		byVault[item.GetVaultPubKey()] = vault
+	}
+	return byChain, byVault
+}
+
+func sumTotals(totals map[string]queueTotals) queueTotals {
+	var sum queueTotals
+	for _, t := range totals {
+		sum.Items += t.Items
+		sum.USD += t.USD
+	}
+	return sum
+}
+
+// growingChains returns the chains whose queue grew by at least minGrowth items
+// since the oldest snapshot while the queues of all other chains did not grow.
+func growingChains(oldest, latest queueSnapshot, minGrowth int) []string {
+	var growing []string
+	for chain, totals := range latest.byChain {
+		growth := totals.Items - oldest.byChain[chain].Items
+		if growth < minGrowth {
+			continue
+		}
+		others := sumTotals(latest.byChain).Items - totals.Items
+		othersBefore := sumTotals(oldest.byChain).Items - oldest.byChain[chain].Items
+		if others <= othersBefore {
+			growing = append(growing, chain)
+		}
+	}
+	sort.Strings(growing)
+	return growing
+}
+
+// describeBacklog lists the backlog of each chain and vault, largest value first.
+func describeBacklog(byChain, byVault map[string]queueTotals) []string {
+	var lines []string
+	for _, group := range []struct {
+		label  string
+		totals map[string]queueTotals
+		name   func(string) string
+	}{
+		{"Chain", byChain, func(s string) string { return s }},
+		{"Vault", byVault, common.ShortenPubKey},
+	} {
+		keys := make([]string, 0, len(group.totals))
+		for key := range group.totals {
+			keys = append(keys, key)
+		}
+		sort.Slice(keys, func(i, j int) bool {
+			a, b := group.totals[keys[i]], group.totals[keys[j]]
+			if a.USD != b.USD {
+				return a.USD > b.USD
+			}
+			return keys[i] < keys[j]
+		})
+		for _, key := range keys {
+			t := group.totals[key]
+			lines = append(lines, fmt.Sprintf("> **%s `%s`:** %d items, %s", group.label, group.name(key), t.Items, common.FormatUSD(t.USD)))
+		}
+	}
+	return lines
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// Check
+////////////////////////////////////////////////////////////////////////////////
+
+func (qm *QueueMonitor) Check() ([]notify.Alert, error) {
+	log.Info().Msg("Checking queue backlog...")
+	cfg := config.Get()
+	qcfg := cfg.QueueMonitor
+
+	client, height, err := common.PinLatestHeight(qm.client)
+	if err != nil {
+		return nil, err
+	}
+	outbound, err := client.GetOutboundQueue()
+	if err != nil {
+		return nil, err
+	}
+	scheduled, err := client.GetScheduledQueue()
+	if err != nil {
+		return nil, err
+	}
+	queue, err := client.GetQueue()
+	if err != nil {
+		return nil, err
+	}
+	prices, err := qm.prices.GetAssetPricesUSD()
+	if err != nil {
+		return nil, err
+	}
+
+	byChain, byVault := queueBacklog(append(append([]openapi.TxOutItem(nil), outbound...), scheduled...), prices)
+	total := sumTotals(byChain)
+
+	// keep the snapshots within the growth window
+	latest := queueSnapshot{height: height, byChain: byChain}
+	qm.history = append(qm.history, latest)
+	for len(qm.history) > 1 && qm.history[0].height < height-qcfg.GrowthWindowBlocks {
+		qm.history = qm.history[1:]
+	}
+	oldest := qm.history[0]
+
+	conditions := make(map[string]string)
+	if total.Items > qcfg.MaxItems {
+		conditions["items"] = fmt.Sprintf("> **Items:** %d (limit %d)", total.Items, qcfg.MaxItems)
+	}
+	if total.USD > qcfg.MaxValueUSD {
+		conditions["value"] = fmt.Sprintf("> **Value:** %s (limit %s)", common.FormatUSD(total.USD), common.FormatUSD(qcfg.MaxValueUSD))
+	}
+	if blocks := height - oldest.height; blocks > 0 {
+		for _, chain := range growingChains(oldest, latest, qcfg.MinChainGrowth) {
+			growth := byChain[chain].Items - oldest.byChain[chain].Items
+			conditions["growth/"+chain] = fmt.Sprintf("> **%s Growing:** +%d items in %d blocks (%.0f per hour) while other chains drain",
+				chain, growth, blocks, float64(growth)*blocksPerHour/float64(blocks))
+		}
+	}
+
+	var lines []string
+	for key, line := range conditions {
+		if !qm.tripped[key] {
+			qm.tripped[key] = true
+			lines = append(lines, line)
+		}
+	}
+	for key := range qm.tripped {
+		if _, ok := conditions[key]; !ok {
+			delete(qm.tripped, key)
+		}
+	}
+	if len(lines) == 0 {
+		return nil, nil
+	}
+
+	sort.Strings(lines)
+	msgs := []string{"### Outbound Queue Backlog"}
+	msgs = append(msgs, lines...)
+	msgs = append(msgs, fmt.Sprintf("> **Queued:** %d outbound, %d scheduled, %d swaps, %d internal",
+		len(outbound), len(scheduled), queue.Swap, queue.Internal))
+	msgs = append(msgs, describeBacklog(byChain, byVault)...)
+	return []notify.Alert{{Webhooks: cfg.Webhooks.Activity, Message: strings.Join(msgs, "\n"), Height: height}}, nil
+}
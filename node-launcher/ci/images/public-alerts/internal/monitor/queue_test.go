@@ -0,0 +1,115 @@
+package monitor
+
+import (
+	"errors"
+	"public-alerts/internal/common"
+	"strings"
+	"testing"
+
+	openapi "gitlab.com/thorchain/thornode/openapi/gen"
+)
+
+func queuedOutbound(chain, vault, asset, amount string) openapi.TxOutItem {
+	return openapi.TxOutItem{Chain: chain, VaultPubKey: &vault, Coin: openapi.Coin{Asset: asset, Amount: amount}}
+}
+
+func queuedOutbounds(n int, chain, vault, asset, amount string) []openapi.TxOutItem {
+	items := make([]openapi.TxOutItem, n)
+	for i := range items {
+		items[i] = queuedOutbound(chain, vault, asset, amount)
+	}
+	return items
+}
+
+func TestGrowingChains(t *testing.T) {
+	snapshot := func(btc, eth int) queueSnapshot {
+		return queueSnapshot{byChain: map[string]queueTotals{"BTC": {Items: btc}, "ETH": {Items: eth}}}
+	}
+	tests := []struct {
+		name     string
+		oldest   queueSnapshot
+		latest   queueSnapshot
+		expected []string
+	}{
+		{"steady", snapshot(5, 5), snapshot(5, 5), nil},
+		{"one chain grows while others drain", snapshot(5, 20), snapshot(20, 10), []string{"BTC"}},
+		{"one chain grows while others hold", snapshot(5, 10), snapshot(20, 10), []string{"BTC"}},
+		{"all chains grow", snapshot(5, 5), snapshot(20, 20), nil},
+		{"growth below minimum", snapshot(5, 20), snapshot(10, 10), nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			growing := growingChains(tt.oldest, tt.latest, 10)
+			if strings.Join(growing, ",") != strings.Join(tt.expected, ",") {
+				t.Errorf("expected %v, got %v", tt.expected, growing)
+			}
+		})
+	}
+}
+
+func TestQueueMonitorCheck(t *testing.T) {
+	client := common.NewFakeClient()
+	client.Height = 1000
+	client.Prices = map[string]float64{"BTC.BTC": 50000, "ETH.ETH": 2000}
+	client.OutboundQueue = append(
+		queuedOutbounds(5, "BTC", "thorpubVAULTA", "BTC.BTC", "100000000"),
+		queuedOutbounds(20, "ETH", "thorpubVAULTB", "ETH.ETH", "100000000")...,
+	)
+
+	qm := NewQueueMonitor(client, client)
+	alerts, err := qm.Check()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(alerts) != 0 {
+		t.Fatalf("expected no alerts for a small backlog, got %v", alerts)
+	}
+
+	// BTC outbounds pile up while ETH drains
+	client.Height = 1300
+	client.OutboundQueue = append(
+		queuedOutbounds(15, "BTC", "thorpubVAULTA", "BTC.BTC", "100000000"),
+		queuedOutbounds(10, "ETH", "thorpubVAULTB", "ETH.ETH", "100000000")...,
+	)
+	client.Scheduled = queuedOutbounds(5, "BTC", "thorpubVAULTA", "BTC.BTC", "100000000")
+	client.Queue = openapi.QueueResponse{Swap: 3}
+	alerts, err = qm.Check()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(alerts) != 1 {
+		t.Fatalf("expected a growth alert, got %v", alerts)
+	}
+	expected := strings.Join([]string{
+		"### Outbound Queue Backlog",
+		"> **BTC Growing:** +15 items in 300 blocks (30 per hour) while other chains drain",
+		"> **Queued:** 25 outbound, 5 scheduled, 3 swaps, 0 internal",
+		"> **Chain `BTC`:** 20 items, $1000000",
+		"> **Chain `ETH`:** 10 items, $20000",
+		"> **Vault `ULTA`:** 20 items, $1000000",
+		"> **Vault `ULTB`:** 10 items, $20000",
+	}, "\n")
+	if alerts[0].Message != expected {
+		t.Errorf("expected %q, got %q", expected, alerts[0].Message)
+	}
+	if alerts[0].Height != 1300 {
+		t.Errorf("expected height 1300, got %d", alerts[0].Height)
+	}
+
+	// the ongoing growth is not repeated, the value limit is new
+	client.Height = 1400
+	client.OutboundQueue = append(client.OutboundQueue, queuedOutbounds(90, "BTC", "thorpubVAULTA", "BTC.BTC", "100000000")...)
+	alerts, err = qm.Check()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(alerts) != 1 || strings.Contains(alerts[0].Message, "Growing") ||
+		!strings.Contains(alerts[0].Message, "> **Value:** $5520000 (limit $5000000)") {
+		t.Fatalf("expected only a value alert, got %v", alerts)
+	}
+
+	client.Err = errors.New("unavailable")
+	if _, err := qm.Check(); err == nil {
+		t.Error("expected error from client")
+	}
+}
@@ -0,0 +1,207 @@
+package monitor
+
+import (
+	"fmt"
+	"public-alerts/internal/common"
+	"public-alerts/internal/config"
+	"public-alerts/internal/notify"
+	"sort"
+	"strconv"
+
+	"github.com/rs/zerolog/log"
+	openapi "gitlab.com/thorchain/thornode/openapi/gen"
+)
+
+// SaversMonitor follows synth utilisation and the savers vaults of the available
+// pools. It alerts when a pool's synth supply approaches the MaxSynthPerPoolDepth
+// mimir cap, when the savers capacity of a pool fills or opens up again, and when
+// savers withdraw more than the configured USD value from a pool between checks.
+type SaversMonitor struct {
+	client  common.ThornodeDataFetcher
+	prices  common.PriceFetcher
+	full    map[string]bool                     // savers capacity full at the last check, by pool
+	savers  map[string]map[string]saverPosition // savers at the last check, by pool and address
+	tripped map[string]bool                     // pools with high synth utilisation already alerted
+}
+
+// saverPosition is the redeemable value of a saver and the height of its last withdrawal.
+type saverPosition struct {
+	redeem       float64
+	lastWithdraw int64
+}
+
+func NewSaversMonitor(client common.ThornodeDataFetcher, prices common.PriceFetcher) *SaversMonitor {
+	return &SaversMonitor{
+		client:  client,
+		prices:  prices,
+		full:    make(map[string]bool),
+		savers:  make(map[string]map[string]saverPosition),
+		tripped: make(map[string]bool),
+	}
+}
+
+func (sm *SaversMonitor) Name() string {
+	return "SaversMonitor"
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// Helpers
+////////////////////////////////////////////////////////////////////////////////
+
+// synthUtilisation returns the synth supply of a pool as a share of twice its
+// asset depth, the base the MaxSynthPerPoolDepth cap applies to.
+func synthUtilisation(pool openapi.Pool) float64 {
+	supply, _ := strconv.ParseFloat(pool.SynthSupply, 64)
+	depth, _ := strconv.ParseFloat(pool.BalanceAsset, 64)
+	if depth <= 0 {
+		return 0
+	}
+	return supply / (2 * depth)
+}
+
+// saversFull reports whether no more synths, and so no savers deposits, can be minted.
+func saversFull(pool openapi.Pool) bool {
+	remaining, _ := strconv.ParseFloat(pool.SynthSupplyRemaining, 64)
+	return pool.SynthMintPaused || remaining <= 0
+}
+
+func saverPositions(savers []openapi.Saver) map[string]saverPosition {
+	positions := make(map[string]saverPosition, len(savers))
+	for _, saver := range savers {
+		redeem, _ := strconv.ParseFloat(saver.AssetRedeemValue, 64)
+		positions[saver.AssetAddress] = saverPosition{redeem: redeem, lastWithdraw: saver.GetLastWithdrawHeight()}
+	}
+	return positions
+}
+
+// saverWithdrawals returns the value each saver withdrew since prev by address,
+// counting savers that left or withdrew again.
+func saverWithdrawals(prev, current map[string]saverPosition) map[string]float64 {
+	withdrawals := make(map[string]float64)
+	for address, before := range prev {
+		after, ok := current[address]
+		if ok && after.lastWithdraw <= before.lastWithdraw {
+			continue
+		}
+		if withdrawn := before.redeem - after.redeem; withdrawn > 0 {
+			withdrawals[address] = withdrawn
+		}
+	}
+	return withdrawals
+}
+
+func formatAssetAmount(amount float64, asset string) string {
+	return fmt.Sprintf("%.2f %s", amount/1e8, assetTicker(asset))
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// Check
+////////////////////////////////////////////////////////////////////////////////
+
+func (sm *SaversMonitor) Check() ([]notify.Alert, error) {
+	log.Info().Msg("Checking savers and synths...")
+	cfg := config.Get()
+
+	client, height, err := common.PinLatestHeight(sm.client)
+	if err != nil {
+		return nil, err
+	}
+	pools, err := client.GetPools()
+	if err != nil {
+		return nil, err
+	}
+	mimir, err := client.GetMimir()
+	if err != nil {
+		return nil, err
+	}
+	prices, err := sm.prices.GetAssetPricesUSD()
+	if err != nil {
+		return nil, err
+	}
+	maxSynths := float64(mimir["MAXSYNTHPERPOOLDEPTH"]) / 10000
+	sort.Slice(pools, func(i, j int) bool { return pools[i].Asset < pools[j].Asset })
+
+	var alerts []notify.Alert
+	alert := func(msg string) {
+		alerts = append(alerts, notify.Alert{Webhooks: cfg.Webhooks.Activity, Message: msg, Height: height})
+	}
+	current := make(map[string]bool, len(pools))
+	for _, pool := range pools {
+		if pool.Status != "Available" {
+			continue
+		}
+		asset := pool.Asset
+		current[asset] = true
+
+		utilisation := synthUtilisation(pool)
+		high := maxSynths > 0 && utilisation >= maxSynths*cfg.SaversMonitor.UtilisationWarning
+		switch {
+		case high && !sm.tripped[asset]:
+			sm.tripped[asset] = true
+			supply, _ := strconv.ParseFloat(pool.SynthSupply, 64)
+			alert(fmt.Sprintf("### Synth Utilisation High: %s\n> **Utilisation:** %s of pool depth (cap %s)\n> **Synth Supply:** %s",
+				asset, common.FormatPercent(utilisation), common.FormatPercent(maxSynths), formatAssetAmount(supply, asset)))
+		case !high:
+			delete(sm.tripped, asset)
+		}
+
+		depth, _ := strconv.ParseFloat(pool.SaversDepth, 64)
+		if depth > 0 {
+			full := saversFull(pool)
+			if prev, ok := sm.full[asset]; ok && prev != full {
+				if full {
+					alert(fmt.Sprintf("### Savers Capacity Full: %s\n> **Savers Depth:** %s (%s)",
+						asset, formatAssetAmount(depth, asset), common.FormatUSD(depth/1e8*prices[asset])))
+				} else {
+					remaining, _ := strconv.ParseFloat(pool.SynthSupplyRemaining, 64)
+					alert(fmt.Sprintf("### Savers Capacity Open: %s\n> **Remaining Capacity:** %s (%s)\n> **Savers Depth:** %s",
+						asset, formatAssetAmount(remaining, asset), common.FormatUSD(remaining/1e8*prices[asset]), formatAssetAmount(depth, asset)))
+				}
+			}
+			sm.full[asset] = full
+		} else {
+			delete(sm.full, asset)
+		}
+
+		// savers are still fetched once after the vault empties to report the withdrawals
+		if _, tracked := sm.savers[asset]; depth <= 0 && !tracked {
+			continue
+		}
+		savers, err := client.GetSavers(asset)
+		if err != nil {
+			return nil, err
+		}
+		positions := saverPositions(savers)
+		if prev, ok := sm.savers[asset]; ok {
+			withdrawals := saverWithdrawals(prev, positions)
+			total, largest := 0.0, ""
+			for address, amount := range withdrawals {
+				total += amount
+				if largest == "" || amount > withdrawals[largest] || (amount == withdrawals[largest] && address < largest) {
+					largest = address
+				}
+			}
+			if usd := total / 1e8 * prices[asset]; usd >= cfg.SaversMonitor.WithdrawalUSD {
+				alert(fmt.Sprintf("### Large Savers Withdrawal: %s\n> **Withdrawn:** %s (%s) by %d savers since the last check\n> **Largest:** `%s` %s\n> **Savers Depth:** %s",
+					asset, formatAssetAmount(total, asset), common.FormatUSD(usd), len(withdrawals),
+					common.ShortenAddress(largest), formatAssetAmount(withdrawals[largest], asset), formatAssetAmount(depth, asset)))
+			}
+		}
+		if depth > 0 {
+			sm.savers[asset] = positions
+		} else {
+			delete(sm.savers, asset)
+		}
+	}
+	for asset := range sm.full {
+		if !current[asset] {
+			delete(sm.full, asset)
+		}
+	}
+	for asset := range sm.savers {
+		if !current[asset] {
+			delete(sm.savers, asset)
+		}
+	}
+	return alerts, nil
+}
@@ -0,0 +1,117 @@
+package monitor
+
+import (
+	"errors"
+	"public-alerts/internal/common"
+	"strings"
+	"testing"
+
+	openapi "gitlab.com/thorchain/thornode/openapi/gen"
+)
+
+func saver(address, redeem string, lastWithdraw int64) openapi.Saver {
+	return openapi.Saver{Asset: "BTC.BTC", AssetAddress: address, AssetRedeemValue: redeem, LastWithdrawHeight: &lastWithdraw}
+}
+
+func TestSaverWithdrawals(t *testing.T) {
+	prev := saverPositions([]openapi.Saver{
+		saver("bc1qleft", "3000000000", 0),
+		saver("bc1qpartial", "2000000000", 0),
+		saver("bc1qyield", "1000000000", 0),
+		saver("bc1qadded", "1000000000", 0),
+	})
+	current := saverPositions([]openapi.Saver{
+		saver("bc1qpartial", "1500000000", 1100),
+		saver("bc1qyield", "990000000", 0),
+		saver("bc1qadded", "2000000000", 0),
+		saver("bc1qnew", "500000000", 0),
+	})
+	withdrawals := saverWithdrawals(prev, current)
+	expected := map[string]float64{"bc1qleft": 3000000000, "bc1qpartial": 500000000}
+	if len(withdrawals) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, withdrawals)
+	}
+	for address, amount := range expected {
+		if withdrawals[address] != amount {
+			t.Errorf("expected %s to withdraw %v, got %v", address, amount, withdrawals[address])
+		}
+	}
+}
+
+func TestSaversMonitorCheck(t *testing.T) {
+	client := common.NewFakeClient()
+	client.Height = 1000
+	client.Mimir["MAXSYNTHPERPOOLDEPTH"] = 3500
+	client.Prices = map[string]float64{"BTC.BTC": 50000}
+	client.Pools = []openapi.Pool{{
+		Asset: "BTC.BTC", Status: "Available", BalanceAsset: "10000000000",
+		SynthSupply: "6400000000", SynthSupplyRemaining: "0", SynthMintPaused: true, SaversDepth: "5000000000",
+	}}
+	client.Savers["BTC.BTC"] = []openapi.Saver{
+		saver("bc1qsaveraaaa", "3000000000", 0),
+		saver("bc1qsaverbbbb", "2000000000", 0),
+	}
+
+	sm := NewSaversMonitor(client, client)
+	alerts, err := sm.Check()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := "### Synth Utilisation High: BTC.BTC\n> **Utilisation:** 32.00% of pool depth (cap 35.00%)\n> **Synth Supply:** 64.00 BTC"
+	if len(alerts) != 1 || alerts[0].Message != expected {
+		t.Fatalf("expected %q, got %v", expected, alerts)
+	}
+
+	// the largest saver leaves, freeing capacity and utilisation
+	client.Height = 1100
+	client.Pools[0].SynthSupply = "5000000000"
+	client.Pools[0].SynthSupplyRemaining = "1000000000"
+	client.Pools[0].SynthMintPaused = false
+	client.Pools[0].SaversDepth = "2000000000"
+	client.Savers["BTC.BTC"] = []openapi.Saver{saver("bc1qsaverbbbb", "2000000000", 0)}
+	alerts, err = sm.Check()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expectedAlerts := []string{
+		strings.Join([]string{
+			"### Savers Capacity Open: BTC.BTC",
+			"> **Remaining Capacity:** 10.00 BTC ($500000)",
+			"> **Savers Depth:** 20.00 BTC",
+		}, "\n"),
+		strings.Join([]string{
+			"### Large Savers Withdrawal: BTC.BTC",
+			"> **Withdrawn:** 30.00 BTC ($1500000) by 1 savers since the last check",
+			"> **Largest:** `bc1q...aaaa` 30.00 BTC",
+			"> **Savers Depth:** 20.00 BTC",
+		}, "\n"),
+	}
+	if len(alerts) != len(expectedAlerts) {
+		t.Fatalf("expected %d alerts, got %v", len(expectedAlerts), alerts)
+	}
+	for i, alert := range alerts {
+		if alert.Message != expectedAlerts[i] {
+			t.Errorf("expected %q, got %q", expectedAlerts[i], alert.Message)
+		}
+		if alert.Height != 1100 {
+			t.Errorf("expected height 1100, got %d", alert.Height)
+		}
+	}
+
+	// a small withdrawal and the capacity filling again
+	client.Pools[0].SynthSupplyRemaining = "0"
+	client.Pools[0].SaversDepth = "1900000000"
+	client.Savers["BTC.BTC"] = []openapi.Saver{saver("bc1qsaverbbbb", "1900000000", 1150)}
+	alerts, err = sm.Check()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(alerts) != 1 || !strings.HasPrefix(alerts[0].Message, "### Savers Capacity Full: BTC.BTC") {
+		t.Fatalf("expected only a capacity full alert, got %v", alerts)
+	}
+
+	client.Err = errors.New("unavailable")
+	if _, err := sm.Check(); err == nil {
+		t.Error("expected error from client")
+	}
+}
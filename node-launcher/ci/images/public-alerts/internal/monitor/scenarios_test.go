@@ -0,0 +1,125 @@
+package monitor
+
+import (
+	"path/filepath"
+	"public-alerts/internal/common"
+	"public-alerts/internal/config"
+	"public-alerts/internal/httpreplay"
+	"strings"
+	"testing"
+	"time"
+)
+
+// replayScenario serves the scenario testdata/scenarios/<name>.json and fails the
+// test if a monitor makes a request the scenario has no response for. The checked-in
+// scenarios are synthetic, written by hand in the recorder's format, and can be
+// replaced by recordings of the same situations.
+func replayScenario(t *testing.T, name string) *httpreplay.Server {
+	t.Helper()
+	scenario, err := httpreplay.Load(filepath.Join("testdata", "scenarios", name+".json"))
+	if err != nil {
+		t.Fatalf("failed to load scenario: %v", err)
+	}
+	server := httpreplay.NewServer(scenario)
+	t.Cleanup(func() {
+		server.Close()
+		if unmatched := server.Unmatched(); len(unmatched) > 0 {
+			t.Errorf("requests missing from scenario %s: %v", name, unmatched)
+		}
+	})
+	return server
+}
+
+// replayThornodeClient returns a THORNode client bound to the replayed scenario.
+func replayThornodeClient(t *testing.T, server *httpreplay.Server) common.ThornodeDataFetcher {
+	t.Helper()
+	client, err := common.NewThornodeClientWithProviders([]config.ThornodeProvider{{
+		Name: "replay",
+		API:  server.URL(httpreplay.ServiceThornode),
+		RPC:  server.URL(httpreplay.ServiceThornodeRPC),
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client
+}
+
+func TestScenarioHaltedChain(t *testing.T) {
+	server := replayScenario(t, "halted_chain")
+
+	clm := NewChainLagMonitor(replayThornodeClient(t, server))
+	clm.lastAlert = time.Now().Add(-2 * time.Hour)
+
+	alerts, err := clm.Check()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(alerts) != 1 {
+		t.Fatalf("expected 1 alert, got %d", len(alerts))
+	}
+	if !strings.Contains(alerts[0].Message, "[ETH] Lagging by over 70 blocks on 3 nodes.") || strings.Contains(alerts[0].Message, "[BTC]") {
+		t.Errorf("unexpected alert message: %s", alerts[0].Message)
+	}
+	if alerts[0].Height != 12000000 {
+		t.Errorf("expected alert at height 12000000, got %d", alerts[0].Height)
+	}
+}
+
+func TestScenarioInsolventVault(t *testing.T) {
+	server := replayScenario(t, "insolvent_vault")
+
+	nineRealms := common.NewNineRealmsClientWithURL(server.URL(httpreplay.ServiceNineRealms))
+	prices := common.NewPriceFetcher(replayThornodeClient(t, server), common.NewMidgardClientWithURL(server.URL(httpreplay.ServiceMidgard)))
+	solvm := NewSolvencyMonitor(nineRealms, prices)
+
+	alerts, err := solvm.Check()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(alerts) != 1 {
+		t.Fatalf("expected 1 alert, got %d", len(alerts))
+	}
+	if !strings.Contains(alerts[0].Message, "Insolvency detected for BTC.BTC at bc1q...sbtc") || strings.Contains(alerts[0].Message, "ETH.ETH") {
+		t.Errorf("unexpected alert message: %s", alerts[0].Message)
+	}
+}
+
+func TestScenarioChurnStuckMigration(t *testing.T) {
+	server := replayScenario(t, "churn")
+
+	om := NewOutboundMonitor(replayThornodeClient(t, server))
+
+	alerts, err := om.Check()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(alerts) != 1 {
+		t.Fatalf("expected 1 alert, got %d", len(alerts))
+	}
+	if !strings.Contains(alerts[0].Message, "/tx/A1B2C3STUCKMIGRATE") || !strings.Contains(alerts[0].Message, "2500000000 BTC.BTC") {
+		t.Errorf("unexpected alert message: %s", alerts[0].Message)
+	}
+	if alerts[0].Height != 12000000 {
+		t.Errorf("expected alert at height 12000000, got %d", alerts[0].Height)
+	}
+}
+
+func TestScenarioChurnMigrationOverdue(t *testing.T) {
+	server := replayScenario(t, "churn")
+
+	client := replayThornodeClient(t, server)
+	mm := NewMigrationMonitor(client, common.NewPriceFetcher(client, common.NewMidgardClientWithURL(server.URL(httpreplay.ServiceMidgard))))
+
+	// the vault started retiring 10000 blocks before the scenario height, beyond the expected window
+	alerts, err := mm.Check()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(alerts) != 1 {
+		t.Fatalf("expected 1 alert, got %d", len(alerts))
+	}
+	if !strings.Contains(alerts[0].Message, "### Retiring Vault Overdue: `ring`") || !strings.Contains(alerts[0].Message, "**Retiring Since:** 11990000 (10000 blocks)") ||
+		!strings.Contains(alerts[0].Message, "**Remaining:** 25.0000 `BTC.BTC`") {
+		t.Errorf("unexpected alert message: %s", alerts[0].Message)
+	}
+}
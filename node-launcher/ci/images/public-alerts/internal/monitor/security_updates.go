@@ -18,6 +18,7 @@ import (
 
 // SecurityUpdateMonitor struct to track last known states
 type SecurityUpdatesMonitor struct {
+	githubAPI    string
 	lastCommit   map[string]string
 	lastBranches map[string]map[string]struct{}
 	lastPRs      map[string]map[int]struct{}
@@ -27,6 +28,7 @@ type SecurityUpdatesMonitor struct {
 // NewSecurityUpdateMonitor initializes the SecurityUpdateMonitor
 func NewSecurityUpdatesMonitor() *SecurityUpdatesMonitor {
 	return &SecurityUpdatesMonitor{
+		githubAPI:    config.Get().Endpoints.GithubAPI,
 		lastCommit:   make(map[string]string),
 		lastBranches: make(map[string]map[string]struct{}),
 		lastPRs:      make(map[string]map[int]struct{}),
@@ -65,7 +67,7 @@ type FetchFunc func(url string, target interface{}) error
 // //////////////////////////////////////////////////////////////////////////////
 
 // checkSecurityUpdates checks for new commits, branches, and PRs, and logs notifications
-func checkSecurityUpdates(fetch FetchFunc, lastCommit map[string]string, lastBranches map[string]map[string]struct{}, lastPRs map[string]map[int]struct{}) ([]notify.Alert, error) {
+func checkSecurityUpdates(fetch FetchFunc, githubAPI string, lastCommit map[string]string, lastBranches map[string]map[string]struct{}, lastPRs map[string]map[int]struct{}) ([]notify.Alert, error) {
 	var alerts []notify.Alert
 	// TODO add to config
 	githubRepos := config.Get().SecurityUpdatesMonitor.Repos
@@ -73,7 +75,7 @@ func checkSecurityUpdates(fetch FetchFunc, lastCommit map[string]string, lastBra
 	for _, repo := range githubRepos {
 		// Check for new commits on master branch
 		log.Info().Msgf("checking %s for commits...", repo)
-		commitURL := fmt.Sprintf("https://api.github.com/repos/%s/branches/master", repo)
+		commitURL := fmt.Sprintf("%s/repos/%s/branches/master", githubAPI, repo)
 		var commitData struct {
 			Commit struct {
 				SHA     string `json:"sha"`
@@ -92,7 +94,7 @@ func checkSecurityUpdates(fetch FetchFunc, lastCommit map[string]string, lastBra
 
 		// Check for new branches
 		log.Info().Msgf("checking %s for new branches...", repo)
-		branchesURL := fmt.Sprintf("https://api.github.com/repos/%s/branches", repo)
+		branchesURL := fmt.Sprintf("%s/repos/%s/branches", githubAPI, repo)
 		var branchesData []struct {
 			Name string `json:"name"`
 		}
@@ -121,7 +123,7 @@ func checkSecurityUpdates(fetch FetchFunc, lastCommit map[string]string, lastBra
 		lastBranches[repo] = branches
 
 		// Check for new PRs
-		prsURL := fmt.Sprintf("https://api.github.com/repos/%s/pulls", repo)
+		prsURL := fmt.Sprintf("%s/repos/%s/pulls", githubAPI, repo)
 		var prsData []struct {
 			Number int    `json:"number"`
 			Title  string `json:"title"`
@@ -171,5 +173,5 @@ func (sum *SecurityUpdatesMonitor) Check() ([]notify.Alert, error) {
 	sum.mu.Lock()
 	defer sum.mu.Unlock()
 	log.Info().Msg("Checking for security updates (TSS Repo)...")
-	return checkSecurityUpdates(fetchJSON, sum.lastCommit, sum.lastBranches, sum.lastPRs)
+	return checkSecurityUpdates(fetchJSON, sum.githubAPI, sum.lastCommit, sum.lastBranches, sum.lastPRs)
 }
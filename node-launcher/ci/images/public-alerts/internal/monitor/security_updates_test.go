@@ -37,7 +37,7 @@ func TestSecurityUpdateMonitor(t *testing.T) {
 	monitor := NewSecurityUpdatesMonitor()
 
 	// Initial run, all states should be new
-	alerts, err := checkSecurityUpdates(mockFetch, monitor.lastCommit, monitor.lastBranches, monitor.lastPRs)
+	alerts, err := checkSecurityUpdates(mockFetch, "https://api.github.com", monitor.lastCommit, monitor.lastBranches, monitor.lastPRs)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -50,7 +50,7 @@ func TestSecurityUpdateMonitor(t *testing.T) {
 	monitor.lastBranches["bnb-chain/tss-lib"] = map[string]struct{}{"old-branch": {}}
 	monitor.lastPRs["bnb-chain/tss-lib"] = map[int]struct{}{0: {}}
 
-	alerts, err = checkSecurityUpdates(mockFetch, monitor.lastCommit, monitor.lastBranches, monitor.lastPRs)
+	alerts, err = checkSecurityUpdates(mockFetch, "https://api.github.com", monitor.lastCommit, monitor.lastBranches, monitor.lastPRs)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -0,0 +1,54 @@
+package monitor
+
+import (
+	"public-alerts/internal/common"
+	"sync"
+	"testing"
+	"time"
+
+	openapi "gitlab.com/thorchain/thornode/openapi/gen"
+)
+
+// sharedFakeClient is a FakeClient that several monitors can pin concurrently, the
+// way they share the caching client in production.
+type sharedFakeClient struct {
+	*common.FakeClient
+}
+
+func (c sharedFakeClient) AtHeight(int) common.ThornodeDataFetcher {
+	return c
+}
+
+// TestMonitorsShareCachingClient runs monitors sorting the same cached pools from
+// their own goroutines. Run with -race to catch monitors modifying shared results.
+func TestMonitorsShareCachingClient(t *testing.T) {
+	fake := common.NewFakeClient()
+	fake.Height = 1000
+	fake.Mimir["MAXSYNTHPERPOOLDEPTH"] = 3500
+	fake.Prices = map[string]float64{"BTC.BTC": 60000, "ETH.ETH": 3000, common.RuneAsset: 1.2}
+	fake.Pools = []openapi.Pool{
+		{Asset: "ETH.ETH", Status: "Available", BalanceAsset: "100000000000", BalanceRune: "250000000000000"},
+		{Asset: "BTC.BTC", Status: "Available", BalanceAsset: "100000000000", BalanceRune: "5000000000000000"},
+	}
+	client := common.NewCachingClient(sharedFakeClient{fake}, time.Minute)
+
+	monitors := []Monitor{NewPoolMonitor(client, fake), NewSaversMonitor(client, fake)}
+	var wg sync.WaitGroup
+	for _, m := range monitors {
+		wg.Add(1)
+		go func(m Monitor) {
+			defer wg.Done()
+			for i := 0; i < 20; i++ {
+				if _, err := m.Check(); err != nil {
+					t.Errorf("%s: unexpected error: %v", m.Name(), err)
+					return
+				}
+			}
+		}(m)
+	}
+	wg.Wait()
+
+	if pools, _ := client.GetPools(); pools[0].Asset != "ETH.ETH" {
+		t.Errorf("expected the cached pools in their original order, got %s first", pools[0].Asset)
+	}
+}
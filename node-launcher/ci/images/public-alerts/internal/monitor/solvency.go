@@ -1,37 +1,19 @@
 package monitor
 
 import (
-	"encoding/json"
 	"fmt"
-	"net/http"
+	"public-alerts/internal/chains"
 	"public-alerts/internal/common"
 	"public-alerts/internal/config"
 	"public-alerts/internal/notify"
 	"strconv"
 	"strings"
+	"sync"
 
 	"github.com/rs/zerolog/log"
+	openapi "gitlab.com/thorchain/thornode/openapi/gen"
 )
 
-type Address struct {
-	Chain   string `json:"chain"`
-	Address string `json:"address"`
-}
-
-type Coin struct {
-	ChainAmount string `json:"chain_amount"`
-	Amount      string `json:"amount"`
-	Asset       string `json:"asset"`
-}
-
-type Vault struct {
-	Status    string    `json:"status"`
-	Addresses []Address `json:"addresses"`
-	Coins     []Coin    `json:"coins"`
-	PubKey    string    `json:"pub_key"`
-	Type      string    `json:"type"`
-}
-
 type Insolvency struct {
 	Asset     string
 	Address   string
@@ -43,7 +25,52 @@ type Insolvency struct {
 	USD       float64
 }
 
+// SolvencyMonitor compares THORChain's accounting of the active asgard vaults with
+// the balances they hold on chain. By default both sides come from the Nine Realms
+// solvency endpoint, in daemon mode the accounting comes from THORNode and the
+// balances from the chain daemons we run.
 type SolvencyMonitor struct {
+	nineRealms common.NineRealmsDataFetcher
+	thornode   common.ThornodeDataFetcher
+	adapters   map[string]chains.ChainAdapter // by chain, set in daemon mode
+	unverified map[string]bool                // chains whose daemon cannot read balances
+	prices     common.PriceFetcher
+
+	lastUnverified string // unverified coins last reported
+
+	mu           sync.Mutex
+	insolvencies []Insolvency // findings of the last check
+}
+
+func NewSolvencyMonitor(nineRealms common.NineRealmsDataFetcher, prices common.PriceFetcher) *SolvencyMonitor {
+	return &SolvencyMonitor{
+		nineRealms: nineRealms,
+		prices:     prices,
+	}
+}
+
+// NewDaemonSolvencyMonitor creates a SolvencyMonitor that does not trust a third
+// party for the on-chain side of the comparison: the balances of the vault addresses
+// are read through the chain adapters. Chains without an adapter are not checked,
+// and the coins of chains whose daemon cannot read balances are reported as
+// unverified.
+func NewDaemonSolvencyMonitor(thornode common.ThornodeDataFetcher, adapters []chains.ChainAdapter, prices common.PriceFetcher) *SolvencyMonitor {
+	byChain := make(map[string]chains.ChainAdapter, len(adapters))
+	unverified := make(map[string]bool)
+	for _, adapter := range adapters {
+		if !chains.ReadsBalances(adapter.Chain()) {
+			log.Warn().Str("chain", adapter.Chain()).Msg("chain daemon cannot read vault balances, solvency is not verified")
+			unverified[adapter.Chain()] = true
+			continue
+		}
+		byChain[adapter.Chain()] = adapter
+	}
+	return &SolvencyMonitor{
+		thornode:   thornode,
+		adapters:   byChain,
+		unverified: unverified,
+		prices:     prices,
+	}
 }
 
 func (solvm *SolvencyMonitor) Name() string {
@@ -54,38 +81,58 @@ func (solvm *SolvencyMonitor) Check() ([]notify.Alert, error) {
 
 	log.Info().Msg("Checking Solvency...")
 	cfg := config.Get()
-	vaults, err := fetchSolvencyData(cfg.Endpoints.NineRealmsAPI)
+	var vaults []common.SolvencyVault
+	var unreadable, unverified []string
+	var err error
+	if solvm.adapters != nil {
+		vaults, unreadable, unverified, err = solvm.daemonSolvency()
+	} else {
+		vaults, err = solvm.nineRealms.GetSolvency()
+	}
 	if err != nil {
 		return nil, err
 	}
 
-	assetPrices, err := common.AssetToUSDViaMidgard(cfg.Endpoints.MidgardAPI)
+	assetPrices, err := solvm.prices.GetAssetPricesUSD()
 	if err != nil {
 		return nil, err
 	}
 
-	return checkSolvency(cfg, vaults, assetPrices)
+	// balances read from our own daemons are real values, empty ones included
+	insolvencies := findInsolvencies(cfg, vaults, assetPrices, solvm.adapters != nil)
+	solvm.mu.Lock()
+	solvm.insolvencies = insolvencies
+	solvm.mu.Unlock()
+
+	alerts := solvencyAlerts(cfg, insolvencies)
+	if len(unreadable) > 0 {
+		msg := fmt.Sprintf("```[ERROR] public-alerts: %s could not read %d vault balances from the chain daemons:\n%s```",
+			solvm.Name(), len(unreadable), strings.Join(unreadable, "\n"))
+		alerts = append(alerts, notify.Alert{Webhooks: cfg.Webhooks.Errors, Message: msg})
+	}
+	// unverified coins are reported when they change, not on every check
+	if joined := strings.Join(unverified, "\n"); joined != solvm.lastUnverified {
+		solvm.lastUnverified = joined
+		if len(unverified) > 0 {
+			msg := fmt.Sprintf("### Solvency Unverified\n> **Reason:** the chain daemon cannot read address balances\n%s", joined)
+			alerts = append(alerts, notify.Alert{Webhooks: cfg.Webhooks.Activity, Message: msg})
+		}
+	}
+	return alerts, nil
+}
+
+// LastInsolvencies returns the insolvencies found by the last check.
+func (solvm *SolvencyMonitor) LastInsolvencies() []Insolvency {
+	solvm.mu.Lock()
+	defer solvm.mu.Unlock()
+	return append([]Insolvency(nil), solvm.insolvencies...)
 }
 
 ////////////////////////////////////////////////////////////////////////////////
 // Helpers
 ////////////////////////////////////////////////////////////////////////////////
 
-func fetchSolvencyData(apiURL string) ([]Vault, error) {
-	resp, err := http.Get(fmt.Sprintf("%s/thorchain/solvency/asgard", apiURL))
-	if err != nil {
-		return nil, fmt.Errorf("error making request: %w", err)
-	}
-	defer resp.Body.Close()
-
-	var vaults []Vault
-	if err := json.NewDecoder(resp.Body).Decode(&vaults); err != nil {
-		return nil, fmt.Errorf("error decoding JSON: %w", err)
-	}
-	return vaults, nil
-}
-
-func FindAddress(addresses []Address, asset string) string {
+func FindAddress(addresses []openapi.VaultAddress, asset string) string {
 
 	// Iterate through the list of addresses
 	for _, address := range addresses {
@@ -100,11 +147,116 @@ func FindAddress(addresses []Address, asset string) string {
 	return ""
 }
 
+// FindRouter returns the router contract of a vault on the chain of an asset.
+func FindRouter(routers []openapi.VaultRouter, asset string) string {
+	chain := strings.Split(asset, ".")[0]
+	for _, router := range routers {
+		if router.GetChain() == chain {
+			return router.GetRouter()
+		}
+	}
+	return ""
+}
+
+// pendingOutbounds sums the coins of the queued outbounds already broadcast, those
+// with an out hash, by vault public key and asset. Outbounds not yet signed are still
+// in the vault, and the gas is only a cap on the fee.
+func pendingOutbounds(items []openapi.TxOutItem) map[string]int64 {
+	pending := make(map[string]int64)
+	for _, item := range items {
+		if item.GetOutHash() == "" {
+			continue
+		}
+		amount, err := strconv.ParseInt(item.Coin.Amount, 10, 64)
+		if err != nil {
+			continue
+		}
+		pending[item.GetVaultPubKey()+"/"+item.Coin.Asset] += amount
+	}
+	return pending
+}
+
+// daemonSolvency pairs the coins of the active asgard vaults with the balances the
+// chain daemons hold for the vault addresses, in the shape of the Nine Realms
+// solvency endpoint. Coins whose balance cannot be read are left out and described
+// in the returned unreadable list, and the coins of chains whose daemon cannot read
+// balances in the unverified list, so the check reports what it could not verify.
+//
+// The accounting is read at a pinned height, the daemons at their latest block. An
+// outbound may leave a vault on chain before THORChain observes it and deducts it,
+// so the broadcast outbounds still queued are added back to its on-chain balance,
+// leaving their gas to the alert thresholds. Inbounds
+// in flight only show as a surplus, and the alert thresholds absorb the remaining
+// skew of a few blocks between the chains.
+func (solvm *SolvencyMonitor) daemonSolvency() (vaults []common.SolvencyVault, unreadable, unverified []string, err error) {
+	client, _, err := common.PinLatestHeight(solvm.thornode)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	asgards, err := client.GetVaults()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	outbounds, err := client.GetOutboundQueue()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	pending := pendingOutbounds(outbounds)
+
+
+	for _, vault := range asgards {
+		if vault.Status != "ActiveVault" {
+			continue
+		}
+		sv := common.SolvencyVault{
+			Status:    vault.Status,
+			Addresses: vault.Addresses,
+			PubKey:    vault.GetPubKey(),
+			Type:      vault.GetType(),
+		}
+		for _, coin := range vault.Coins {
+			chain := strings.Split(coin.Asset, ".")[0]
+			address := FindAddress(vault.Addresses, coin.Asset)
+			if address == "" {
+				continue
+			}
+			if solvm.unverified[chain] {
+				unverified = append(unverified, fmt.Sprintf("> `%s` at `%s`", coin.Asset, address))
+				continue
+			}
+			adapter, ok := solvm.adapters[chain]
+			if !ok {
+				continue
+			}
+			balance, err := adapter.Balance(address, FindRouter(vault.Routers, coin.Asset), coin.Asset)
+			if err != nil {
+				log.Error().Err(err).Str("asset", coin.Asset).Str("address", address).Msg("failed to read vault balance from chain daemon")
+				unreadable = append(unreadable, fmt.Sprintf("%s at %s: %v", coin.Asset, address, err))
+				continue
+			}
+			sv.Coins = append(sv.Coins, common.SolvencyCoin{
+				Asset:       coin.Asset,
+				Amount:      coin.Amount,
+				ChainAmount: strconv.FormatInt(balance+pending[vault.GetPubKey()+"/"+coin.Asset], 10),
+			})
+		}
+		vaults = append(vaults, sv)
+	}
+	return vaults, unreadable, unverified, nil
+}
+
 ////////////////////////////////////////////////////////////////////////////////
 // Check Solvency
 ////////////////////////////////////////////////////////////////////////////////
 
-func checkSolvency(cfg config.Config, vaults []Vault, assetPrices map[string]float64) ([]notify.Alert, error) {
+func checkSolvency(cfg config.Config, vaults []common.SolvencyVault, assetPrices map[string]float64) ([]notify.Alert, error) {
+	return solvencyAlerts(cfg, findInsolvencies(cfg, vaults, assetPrices, false)), nil
+}
+
+// findInsolvencies returns the coins of the active vaults holding less on chain than
+// THORChain accounts for. Zero chain amounts are skipped unless countEmpty is set,
+// as the Nine Realms endpoint does not tell an empty address from an untracked coin.
+func findInsolvencies(cfg config.Config, vaults []common.SolvencyVault, assetPrices map[string]float64, countEmpty bool) []Insolvency {
 	var insolvencies []Insolvency
 
 	for _, vault := range vaults {
@@ -114,7 +266,7 @@ func checkSolvency(cfg config.Config, vaults []Vault, assetPrices map[string]flo
 
 		for _, coin := range vault.Coins {
 			chainAmount, err := strconv.Atoi(coin.ChainAmount)
-			if err != nil || chainAmount == 0 {
+			if err != nil || (chainAmount == 0 && !countEmpty) {
 				continue
 			}
 
@@ -124,7 +276,13 @@ func checkSolvency(cfg config.Config, vaults []Vault, assetPrices map[string]flo
 			}
 
 			diff := chainAmount - amount
-			pctDiff := float64(diff) / float64(chainAmount)
+			pctDiff := 0.0
+			switch {
+			case chainAmount > 0:
+				pctDiff = float64(diff) / float64(chainAmount)
+			case amount > 0:
+				pctDiff = -1 // the address was drained
+			}
 
 			// calculate USD diff
 			assetPrice, ok := assetPrices[coin.Asset]
@@ -154,6 +312,10 @@ func checkSolvency(cfg config.Config, vaults []Vault, assetPrices map[string]flo
 		}
 	}
 
+	return insolvencies
+}
+
+func solvencyAlerts(cfg config.Config, insolvencies []Insolvency) []notify.Alert {
 	// Compose alerts based on insolvencies
 	var alertMsgs []string
 
@@ -165,9 +327,9 @@ func checkSolvency(cfg config.Config, vaults []Vault, assetPrices map[string]flo
 		}
 		finalMsg := "```" + strings.Join(alertMsgs, "\n") + "```"
 
-		return []notify.Alert{{Webhooks: cfg.Webhooks.Activity, Message: finalMsg}}, nil
+		return []notify.Alert{{Webhooks: cfg.Webhooks.Activity, Message: finalMsg}}
 	} else {
-		return nil, nil
+		return nil
 	}
 
 }
@@ -1,9 +1,15 @@
 package monitor
 
 import (
+	"errors"
+	"public-alerts/internal/chains"
+	"public-alerts/internal/common"
 	"public-alerts/internal/config"
+	"reflect"
 	"strings"
 	"testing"
+
+	openapi "gitlab.com/thorchain/thornode/openapi/gen"
 )
 
 func TestCheckSolvency(t *testing.T) {
@@ -17,12 +23,12 @@ func TestCheckSolvency(t *testing.T) {
 		},
 	}
 
-	vaults := []Vault{{
-		Addresses: []Address{{
+	vaults := []common.SolvencyVault{{
+		Addresses: []openapi.VaultAddress{{
 			Chain:   "BTC",
 			Address: "1BitcoinAddress",
 		}},
-		Coins: []Coin{{
+		Coins: []common.SolvencyCoin{{
 			ChainAmount: "900",  // What is Actually in the vault
 			Amount:      "1000", // What TC thinks is in the vault
 			Asset:       "BTC",
@@ -56,3 +62,178 @@ func TestCheckSolvency(t *testing.T) {
 		t.Errorf("Expected no alerts, got %d", len(alerts))
 	}
 }
+
+func TestSolvencyMonitorCheck(t *testing.T) {
+	client := common.NewFakeClient()
+	client.Prices["BTC.BTC"] = 50000
+	client.Solvency = []common.SolvencyVault{
+		{
+			Addresses: []openapi.VaultAddress{{Chain: "BTC", Address: "bc1qactivevaultaddress"}},
+			Coins:     []common.SolvencyCoin{{ChainAmount: "900", Amount: "1000", Asset: "BTC.BTC"}},
+			Status:    "ActiveVault",
+			PubKey:    "thorpubactive",
+			Type:      "AsgardVault",
+		},
+		{
+			// retiring vaults are not considered
+			Addresses: []openapi.VaultAddress{{Chain: "BTC", Address: "bc1qretiringvaultaddress"}},
+			Coins:     []common.SolvencyCoin{{ChainAmount: "500", Amount: "1000", Asset: "BTC.BTC"}},
+			Status:    "RetiringVault",
+			PubKey:    "thorpubretiring",
+			Type:      "AsgardVault",
+		},
+	}
+
+	solvm := NewSolvencyMonitor(client, client)
+	alerts, err := solvm.Check()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(alerts) != 1 {
+		t.Fatalf("expected 1 alert, got %d", len(alerts))
+	}
+	if !strings.Contains(alerts[0].Message, "Insolvency detected for BTC.BTC at bc1q...ress") {
+		t.Errorf("unexpected alert message: %s", alerts[0].Message)
+	}
+	if strings.Count(alerts[0].Message, "Insolvency detected") != 1 {
+		t.Errorf("expected a single insolvency, got: %s", alerts[0].Message)
+	}
+	if found := solvm.LastInsolvencies(); len(found) != 1 || found[0].Asset != "BTC.BTC" || found[0].Diff != "-11.11%" {
+		t.Errorf("expected the insolvency to be kept for other monitors, got %+v", found)
+	}
+
+	// missing prices fail the check
+	client.Prices = nil
+	client.Err = errors.New("midgard unavailable")
+	if _, err := solvm.Check(); err == nil {
+		t.Error("expected error when the price source fails")
+	}
+}
+
+func TestDaemonSolvencyMonitorCheck(t *testing.T) {
+	const usdc = "ETH.USDC-0XA0B86991C6218B36C1D19D4A2E9EB0CE3606EB48"
+	client := common.NewFakeClient()
+	client.Height = 1000
+	client.Prices["BTC.BTC"] = 50000
+	client.Prices[usdc] = 1
+	client.Prices["GAIA.ATOM"] = 10
+
+	ethChain, router := "ETH", "0xrouter"
+	active := asgard("thorpubactive", "ActiveVault",
+		openapi.VaultAddress{Chain: "BTC", Address: "bc1qactivevaultaddress"},
+		openapi.VaultAddress{Chain: "ETH", Address: "0xactivevault"},
+		openapi.VaultAddress{Chain: "GAIA", Address: "cosmos1activevault"})
+	active.Routers = []openapi.VaultRouter{{Chain: &ethChain, Router: &router}}
+	active.Coins = []openapi.Coin{
+		{Asset: "BTC.BTC", Amount: "1000"},
+		{Asset: usdc, Amount: "5000"},
+		{Asset: "GAIA.ATOM", Amount: "1000"}, // no gaia daemon, not checked
+	}
+	// retiring vaults are not considered, their balances are not read
+	retiring := asgard("thorpubretiring", "RetiringVault",
+		openapi.VaultAddress{Chain: "BTC", Address: "bc1qretiringvaultaddress"})
+	retiring.Coins = []openapi.Coin{{Asset: "BTC.BTC", Amount: "1000"}}
+	client.Vaults = []openapi.Vault{active, retiring}
+
+	btc := &fakeAdapter{chain: "BTC", balances: map[string]int64{
+		"bc1qactivevaultaddress//BTC.BTC": 900,
+	}}
+	eth := &fakeAdapter{chain: "ETH", balances: map[string]int64{
+		"0xactivevault/0xrouter/" + usdc: 5000,
+	}}
+	solvm := NewDaemonSolvencyMonitor(client, []chains.ChainAdapter{btc, eth}, client)
+
+	alerts, err := solvm.Check()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(alerts) != 1 || !strings.Contains(alerts[0].Message, "Insolvency detected for BTC.BTC at bc1q...ress") {
+		t.Fatalf("expected the btc insolvency, got %v", alerts)
+	}
+	if strings.Count(alerts[0].Message, "Insolvency detected") != 1 {
+		t.Errorf("expected a single insolvency, got: %s", alerts[0].Message)
+	}
+	if client.PinnedHeight != 1000 {
+		t.Errorf("expected the accounting to be read at the pinned height, got %d", client.PinnedHeight)
+	}
+
+	// an outbound not broadcast yet is still in the vault and hides no shortfall
+	outHash := "A1B2C3BROADCAST"
+	client.OutboundQueue = []openapi.TxOutItem{queuedOutbound("BTC", "thorpubactive", "BTC.BTC", "100")}
+	client.OutboundQueue[0].MaxGas = []openapi.Coin{{Asset: "BTC.BTC", Amount: "10"}}
+	if alerts, err = solvm.Check(); err != nil || len(alerts) != 1 || !strings.Contains(alerts[0].Message, "BTC.BTC") {
+		t.Fatalf("expected the vault to stay insolvent with an unbroadcast outbound, got %v, %v", alerts, err)
+	}
+
+	// a broadcast outbound may have left the vault before THORChain observed it, its
+	// gas is not credited
+	client.OutboundQueue[0].OutHash = &outHash
+	if alerts, err = solvm.Check(); err != nil || len(alerts) != 0 {
+		t.Fatalf("expected the broadcast outbound to be tolerated, got %v, %v", alerts, err)
+	}
+	client.OutboundQueue[0].Coin.Amount = "50"
+	if alerts, err = solvm.Check(); err != nil || len(alerts) != 1 || !strings.Contains(alerts[0].Message, "BTC.BTC") {
+		t.Fatalf("expected a shortfall beyond the broadcast outbound to be reported, got %v, %v", alerts, err)
+	}
+	client.OutboundQueue = nil
+
+	// balances a daemon fails to read are reported on the errors webhooks, a drained
+	// address is an insolvency
+	btc.err = errors.New("unavailable")
+	eth.balances["0xactivevault/0xrouter/"+usdc] = 0
+	alerts, err = solvm.Check()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(alerts) != 2 {
+		t.Fatalf("expected an insolvency and an error alert, got %v", alerts)
+	}
+	if !strings.Contains(alerts[0].Message, "Insolvency detected for "+usdc) || strings.Contains(alerts[0].Message, "BTC.BTC") {
+		t.Errorf("expected only the usdc insolvency, got: %s", alerts[0].Message)
+	}
+	if !reflect.DeepEqual(alerts[1].Webhooks, config.Get().Webhooks.Errors) ||
+		!strings.Contains(alerts[1].Message, "could not read 1 vault balances") ||
+		!strings.Contains(alerts[1].Message, "BTC.BTC at bc1qactivevaultaddress: unavailable") {
+		t.Errorf("unexpected error alert: %+v", alerts[1])
+	}
+	if found := solvm.LastInsolvencies(); len(found) != 1 || found[0].Diff != "-100.00%" {
+		t.Errorf("expected the drained usdc to be kept for other monitors, got %+v", found)
+	}
+
+	client.Err = errors.New("unavailable")
+	if _, err := solvm.Check(); err == nil {
+		t.Error("expected error when thornode is unavailable")
+	}
+}
+
+func TestDaemonSolvencyMonitorUnverified(t *testing.T) {
+	client := common.NewFakeClient()
+	client.Height = 1000
+	client.Prices["BTC.BTC"] = 50000
+	client.Prices["DOGE.DOGE"] = 0.1
+	active := asgard("thorpubactive", "ActiveVault",
+		openapi.VaultAddress{Chain: "BTC", Address: "bc1qactivevaultaddress"},
+		openapi.VaultAddress{Chain: "DOGE", Address: "DActiveVaultAddress"})
+	active.Coins = []openapi.Coin{{Asset: "BTC.BTC", Amount: "1000"}, {Asset: "DOGE.DOGE", Amount: "1000"}}
+	client.Vaults = []openapi.Vault{active}
+
+	// dogecoind cannot scan the UTXO set, its balances are never read
+	btc := &fakeAdapter{chain: "BTC", balances: map[string]int64{"bc1qactivevaultaddress//BTC.BTC": 1000}}
+	doge := &fakeAdapter{chain: "DOGE", err: errors.New("unexpected balance read")}
+	solvm := NewDaemonSolvencyMonitor(client, []chains.ChainAdapter{btc, doge}, client)
+
+	alerts, err := solvm.Check()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(alerts) != 1 || !reflect.DeepEqual(alerts[0].Webhooks, config.Get().Webhooks.Activity) ||
+		!strings.Contains(alerts[0].Message, "### Solvency Unverified") ||
+		!strings.Contains(alerts[0].Message, "`DOGE.DOGE` at `DActiveVaultAddress`") {
+		t.Fatalf("expected the DOGE vault to be reported unverified, got %v", alerts)
+	}
+
+	// reported again only when the unverified coins change
+	if alerts, err = solvm.Check(); err != nil || len(alerts) != 0 {
+		t.Errorf("expected no repeated alert, got %v, %v", alerts, err)
+	}
+}
@@ -0,0 +1,65 @@
+package monitor
+
+import (
+	"errors"
+	"public-alerts/internal/common"
+	"public-alerts/internal/config"
+	"strings"
+	"testing"
+
+	openapi "gitlab.com/thorchain/thornode/openapi/gen"
+)
+
+func TestOutboundMonitorCheck(t *testing.T) {
+	threshold := int64(config.Get().StuckOutboundMonitor.BlockAgeThreshold)
+	stuckHash, freshHash := "STUCK", "FRESH"
+	stuckHeight, freshHeight := int64(1000), 1000+threshold
+
+	client := common.NewFakeClient()
+	client.Height = int(1000 + threshold + 1)
+	client.OutboundQueue = []openapi.TxOutItem{
+		{Chain: "BTC", InHash: &stuckHash, Coin: openapi.Coin{Asset: "BTC.BTC", Amount: "100"}},
+		{Chain: "ETH", InHash: &freshHash, Coin: openapi.Coin{Asset: "ETH.ETH", Amount: "200"}},
+	}
+	client.TxDetails[stuckHash] = &openapi.TxDetailsResponse{FinalisedHeight: &stuckHeight}
+	client.TxDetails[freshHash] = &openapi.TxDetailsResponse{FinalisedHeight: &freshHeight}
+
+	om := NewOutboundMonitor(client)
+
+	// only the outbound older than the threshold should alert
+	alerts, err := om.Check()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(alerts) != 1 {
+		t.Fatalf("expected 1 alert, got %d", len(alerts))
+	}
+	if !strings.Contains(alerts[0].Message, "/tx/STUCK") || !strings.Contains(alerts[0].Message, "100 BTC.BTC") {
+		t.Errorf("unexpected alert message: %s", alerts[0].Message)
+	}
+	if alerts[0].Height != client.Height || client.PinnedHeight != client.Height {
+		t.Errorf("expected reads and alert to be pinned to %d, got pinned=%d alert=%d", client.Height, client.PinnedHeight, alerts[0].Height)
+	}
+
+	// already alerted outbounds are not repeated
+	alerts, err = om.Check()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(alerts) != 0 {
+		t.Errorf("expected no repeated alerts, got %d", len(alerts))
+	}
+
+	// missing tx details are skipped rather than failing the check
+	missingHash := "MISSING"
+	client.OutboundQueue = append(client.OutboundQueue, openapi.TxOutItem{InHash: &missingHash})
+	if _, err = om.Check(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	// fetch errors are surfaced
+	client.Err = errors.New("unavailable")
+	if _, err = om.Check(); err == nil {
+		t.Error("expected error when the client fails")
+	}
+}
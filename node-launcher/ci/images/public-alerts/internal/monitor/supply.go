@@ -0,0 +1,129 @@
+package monitor
+
+import (
+	"fmt"
+	"public-alerts/internal/common"
+	"public-alerts/internal/config"
+	"public-alerts/internal/notify"
+	"strconv"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Emission constants used when the mimir does not override them.
+const (
+	defaultEmissionCurve = 6
+	defaultBlocksPerYear = 5256000
+)
+
+// SupplyMonitor tracks the total RUNE supply of the bank module and the reserve
+// reported by /thorchain/network. Block rewards are paid out of the reserve, so
+// the supply should not grow, and the reserve should not drop faster than the
+// block rewards and the outbound gas it subsidises explain. Either is a security
+// alert, as it points at an unexpected mint or a reserve drain.
+type SupplyMonitor struct {
+	client common.ThornodeDataFetcher
+	last   *supplySample // nil before the first check
+}
+
+// supplySample is the supply and reserve at a height, in 1e8 RUNE.
+type supplySample struct {
+	height     int
+	supply     float64
+	reserve    float64
+	gasSubsidy float64 // cumulative outbound gas spent beyond the gas withheld from users
+}
+
+func NewSupplyMonitor(client common.ThornodeDataFetcher) *SupplyMonitor {
+	return &SupplyMonitor{client: client}
+}
+
+func (sm *SupplyMonitor) Name() string {
+	return "SupplyMonitor"
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// Helpers
+////////////////////////////////////////////////////////////////////////////////
+
+// blockReward returns the RUNE emitted from reserve per block.
+func blockReward(reserve float64, mimir map[string]int64) float64 {
+	curve, blocks := float64(defaultEmissionCurve), float64(defaultBlocksPerYear)
+	if v := mimir["EMISSIONCURVE"]; v > 0 {
+		curve = float64(v)
+	}
+	if v := mimir["BLOCKSPERYEAR"]; v > 0 {
+		blocks = float64(v)
+	}
+	return reserve / curve / blocks
+}
+
+// reserveDrainAllowance returns the reserve decrease explained by the block rewards
+// and the gas subsidy between prev and current.
+func reserveDrainAllowance(prev, current supplySample, mimir map[string]int64) (rewards, subsidy float64) {
+	rewards = float64(current.height-prev.height) * blockReward(prev.reserve, mimir)
+	if delta := current.gasSubsidy - prev.gasSubsidy; delta > 0 {
+		subsidy = delta
+	}
+	return rewards, subsidy
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// Check
+////////////////////////////////////////////////////////////////////////////////
+
+func (sm *SupplyMonitor) Check() ([]notify.Alert, error) {
+	log.Info().Msg("Checking RUNE supply and reserve...")
+	cfg := config.Get()
+
+	client, height, err := common.PinLatestHeight(sm.client)
+	if err != nil {
+		return nil, err
+	}
+	network, err := client.GetNetwork()
+	if err != nil {
+		return nil, err
+	}
+	supply, err := client.GetRuneSupply()
+	if err != nil {
+		return nil, err
+	}
+	mimir, err := client.GetMimir()
+	if err != nil {
+		return nil, err
+	}
+
+	current := supplySample{height: height, supply: float64(supply)}
+	if current.reserve, err = strconv.ParseFloat(network.TotalReserve, 64); err != nil {
+		return nil, fmt.Errorf("invalid total reserve %q: %w", network.TotalReserve, err)
+	}
+	spent, _ := strconv.ParseFloat(network.GasSpentRune, 64)
+	withheld, _ := strconv.ParseFloat(network.GasWithheldRune, 64)
+	current.gasSubsidy = spent - withheld
+
+	prev := sm.last
+	if prev != nil && height <= prev.height {
+		return nil, nil // a lagging provider, compare once the chain has moved on
+	}
+	sm.last = &current
+	if prev == nil {
+		return nil, nil
+	}
+
+	var alerts []notify.Alert
+	blocks := height - prev.height
+	if increase := current.supply - prev.supply; increase > cfg.SupplyMonitor.MaxSupplyIncrease*1e8 {
+		msg := fmt.Sprintf("### RUNE Supply Increased\n> **Supply:** %s → %s (+%s) over %d blocks\n> **Allowed:** %s",
+			formatRune(prev.supply), formatRune(current.supply), formatRune(increase), blocks, formatRune(cfg.SupplyMonitor.MaxSupplyIncrease*1e8))
+		alerts = append(alerts, notify.Alert{Webhooks: cfg.Webhooks.Security, Message: msg, Height: height})
+	}
+
+	rewards, subsidy := reserveDrainAllowance(*prev, current, mimir)
+	if drop := prev.reserve - current.reserve; drop > (rewards+subsidy)*cfg.SupplyMonitor.ReserveDrainFactor {
+		msg := fmt.Sprintf("### Reserve Drain\n> **Reserve:** %s → %s (-%s) over %d blocks\n> **Explained:** %s (block rewards %s, gas subsidy %s, limit %.1fx)",
+			formatRune(prev.reserve), formatRune(current.reserve), formatRune(drop), blocks,
+			formatRune(rewards+subsidy), formatRune(rewards), formatRune(subsidy), cfg.SupplyMonitor.ReserveDrainFactor)
+		alerts = append(alerts, notify.Alert{Webhooks: cfg.Webhooks.Security, Message: msg, Height: height})
+	}
+	return alerts, nil
+}
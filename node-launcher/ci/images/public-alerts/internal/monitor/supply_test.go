@@ -0,0 +1,86 @@
+package monitor
+
+import (
+	"errors"
+	"public-alerts/internal/common"
+	"strings"
+	"testing"
+
+	openapi "gitlab.com/thorchain/thornode/openapi/gen"
+)
+
+func TestBlockReward(t *testing.T) {
+	reserve := 6 * 5256000 * 10e8
+	if reward := blockReward(reserve, nil); reward != 10e8 {
+		t.Errorf("expected 10 RUNE per block, got %v", reward)
+	}
+	if reward := blockReward(reserve, map[string]int64{"EMISSIONCURVE": 12}); reward != 5e8 {
+		t.Errorf("expected 5 RUNE per block with the mimir curve, got %v", reward)
+	}
+}
+
+func TestSupplyMonitorCheck(t *testing.T) {
+	client := common.NewFakeClient()
+	client.Height = 1000
+	client.RuneSupply = 50000000e8
+	client.Network = &openapi.NetworkResponse{TotalReserve: "31536000000000000", GasSpentRune: "100000000000", GasWithheldRune: "50000000000"}
+
+	sm := NewSupplyMonitor(client)
+	alerts, err := sm.Check()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(alerts) != 0 {
+		t.Fatalf("expected the first check to record only, got %v", alerts)
+	}
+
+	// fees burn supply, block rewards and gas subsidy drain the reserve
+	client.Height = 1100
+	client.RuneSupply -= 5e8
+	client.Network = &openapi.NetworkResponse{TotalReserve: "31535900000000000", GasSpentRune: "120000000000", GasWithheldRune: "50000000000"}
+	if alerts, err = sm.Check(); err != nil || len(alerts) != 0 {
+		t.Fatalf("expected no alerts for normal emission, got %v, %v", alerts, err)
+	}
+
+	// a lagging provider is skipped
+	client.Height = 1050
+	if alerts, err = sm.Check(); err != nil || len(alerts) != 0 {
+		t.Fatalf("expected no alerts for an older height, got %v, %v", alerts, err)
+	}
+
+	client.Height = 1200
+	client.RuneSupply += 100e8
+	client.Network = &openapi.NetworkResponse{TotalReserve: "31535400000000000", GasSpentRune: "120000000000", GasWithheldRune: "50000000000"}
+	alerts, err = sm.Check()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []string{
+		strings.Join([]string{
+			"### RUNE Supply Increased",
+			"> **Supply:** 49999995 RUNE → 50000095 RUNE (+100 RUNE) over 100 blocks",
+			"> **Allowed:** 0 RUNE",
+		}, "\n"),
+		strings.Join([]string{
+			"### Reserve Drain",
+			"> **Reserve:** 315359000 RUNE → 315354000 RUNE (-5000 RUNE) over 100 blocks",
+			"> **Explained:** 1000 RUNE (block rewards 1000 RUNE, gas subsidy 0 RUNE, limit 2.0x)",
+		}, "\n"),
+	}
+	if len(alerts) != len(expected) {
+		t.Fatalf("expected %d alerts, got %v", len(expected), alerts)
+	}
+	for i, alert := range alerts {
+		if alert.Message != expected[i] {
+			t.Errorf("expected %q, got %q", expected[i], alert.Message)
+		}
+		if alert.Height != 1200 {
+			t.Errorf("expected height 1200, got %d", alert.Height)
+		}
+	}
+
+	client.Err = errors.New("unavailable")
+	if _, err := sm.Check(); err == nil {
+		t.Error("expected error from client")
+	}
+}
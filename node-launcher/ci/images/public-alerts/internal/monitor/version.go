@@ -0,0 +1,271 @@
+package monitor
+
+import (
+	"fmt"
+	"public-alerts/internal/common"
+	"public-alerts/internal/config"
+	"public-alerts/internal/notify"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+	openapi "gitlab.com/thorchain/thornode/openapi/gen"
+)
+
+// VersionMonitor follows THORNode version rollouts from the versions the nodes
+// report. It alerts when a new version appears, when a version's adoption in the
+// active set crosses 1/3 and 2/3, when the network's effective version is bumped,
+// and when watched nodes are left behind the current version. Upgrade proposals
+// are reported with their scheduled height as they are proposed, voted on and
+// approved. Docker image tags are tracked separately by ImageChangeMonitor.
+type VersionMonitor struct {
+	client      common.ThornodeDataFetcher
+	watched     []config.WatchedNode
+	initialized bool
+	current     string                            // effective network version at the last check
+	adoption    map[string]int                    // adoption level reached per version, see adoptionLevel
+	proposals   map[string]common.UpgradeProposal // upgrade proposals at the last check by name
+	lagging     map[string]string                 // watched nodes behind the current version
+}
+
+func NewVersionMonitor(client common.ThornodeDataFetcher, watched []config.WatchedNode) *VersionMonitor {
+	return &VersionMonitor{
+		client:    client,
+		watched:   watched,
+		adoption:  make(map[string]int),
+		proposals: make(map[string]common.UpgradeProposal),
+		lagging:   make(map[string]string),
+	}
+}
+
+func (vm *VersionMonitor) Name() string {
+	return "VersionMonitor"
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// Helpers
+////////////////////////////////////////////////////////////////////////////////
+
+// compareVersions compares two dotted versions numerically, returning -1, 0 or 1.
+func compareVersions(a, b string) int {
+	as := strings.Split(strings.TrimPrefix(a, "v"), ".")
+	bs := strings.Split(strings.TrimPrefix(b, "v"), ".")
+	for i := 0; i < len(as) || i < len(bs); i++ {
+		var x, y int
+		if i < len(as) {
+			x, _ = strconv.Atoi(as[i])
+		}
+		if i < len(bs) {
+			y, _ = strconv.Atoi(bs[i])
+		}
+		switch {
+		case x < y:
+			return -1
+		case x > y:
+			return 1
+		}
+	}
+	return 0
+}
+
+// versionCounts counts the versions run by all nodes and by active nodes.
+func versionCounts(nodes []openapi.Node) (all, active map[string]int) {
+	all, active = make(map[string]int), make(map[string]int)
+	for _, node := range nodes {
+		if node.Version == "" {
+			continue
+		}
+		all[node.Version]++
+		if node.Status == "Active" {
+			active[node.Version]++
+		}
+	}
+	return all, active
+}
+
+// adoptionLevel returns 2 when count is a 2/3 supermajority of total, 1 when it
+// is at least a third and 0 otherwise.
+func adoptionLevel(count, total int) int {
+	switch {
+	case total == 0:
+		return 0
+	case count >= supermajority(total):
+		return 2
+	case 3*count >= total:
+		return 1
+	}
+	return 0
+}
+
+func sortedVersions(counts map[string]int) []string {
+	versions := make([]string, 0, len(counts))
+	for version := range counts {
+		versions = append(versions, version)
+	}
+	sort.Slice(versions, func(i, j int) bool { return compareVersions(versions[i], versions[j]) < 0 })
+	return versions
+}
+
+// formatApproval formats the approved share of an upgrade proposal as a percentage.
+func formatApproval(p common.UpgradeProposal) string {
+	share, err := strconv.ParseFloat(p.ApprovedPercent, 64)
+	if err != nil {
+		return p.ApprovedPercent
+	}
+	return fmt.Sprintf("%.1f%%", share*100)
+}
+
+// describeProposal returns the detail lines of an upgrade proposal, with the
+// approval change since prev when given.
+func describeProposal(p common.UpgradeProposal, prev *common.UpgradeProposal, height int) []string {
+	lines := []string{fmt.Sprintf("> **Scheduled Height:** %d (in %d blocks)", p.Height, int(p.Height)-height)}
+	approval := "> **Approval:** " + formatApproval(p)
+	if prev != nil {
+		approval = fmt.Sprintf("> **Approval:** %s → %s", formatApproval(*prev), formatApproval(p))
+	}
+	if !p.Approved {
+		approval += fmt.Sprintf(", %d more validators to quorum", p.ValidatorsToQuorum)
+	}
+	lines = append(lines, approval)
+	if p.Info != "" {
+		lines = append(lines, "> **Info:** "+p.Info)
+	}
+	return lines
+}
+
+// proposalChanges describes new, newly voted and newly approved upgrade proposals.
+func proposalChanges(prev map[string]common.UpgradeProposal, proposals []common.UpgradeProposal, height int) []string {
+	var msgs []string
+	for _, p := range proposals {
+		old, seen := prev[p.Name]
+		var title string
+		switch {
+		case !seen:
+			title = "Upgrade Proposed"
+		case p.Approved && !old.Approved:
+			title = "Upgrade Approved"
+		case p.ApprovedPercent != old.ApprovedPercent:
+			title = "Upgrade Votes"
+		default:
+			continue
+		}
+		var previous *common.UpgradeProposal
+		if seen {
+			previous = &old
+		}
+		lines := append([]string{fmt.Sprintf("### %s: `%s`", title, p.Name)}, describeProposal(p, previous, height)...)
+		msgs = append(msgs, strings.Join(lines, "\n"))
+	}
+	return msgs
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// Check
+////////////////////////////////////////////////////////////////////////////////
+
+func (vm *VersionMonitor) Check() ([]notify.Alert, error) {
+	log.Info().Msg("Checking node versions...")
+	cfg := config.Get()
+
+	client, height, err := common.PinLatestHeight(vm.client)
+	if err != nil {
+		return nil, err
+	}
+	nodes, err := client.GetNodes()
+	if err != nil {
+		return nil, err
+	}
+	version, err := client.GetVersion()
+	if err != nil {
+		return nil, err
+	}
+	proposals, err := client.GetUpgradeProposals()
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(proposals, func(i, j int) bool { return proposals[i].Name < proposals[j].Name })
+
+	all, active := versionCounts(nodes)
+	activeTotal := 0
+	for _, count := range active {
+		activeTotal += count
+	}
+
+	var alerts []notify.Alert
+	alert := func(msg string) {
+		alerts = append(alerts, notify.Alert{Webhooks: cfg.Webhooks.Activity, Message: msg, Height: height})
+	}
+
+	for _, v := range sortedVersions(all) {
+		level, known := vm.adoption[v]
+		newLevel := adoptionLevel(active[v], activeTotal)
+		vm.adoption[v] = newLevel
+		if !vm.initialized {
+			continue
+		}
+		if !known {
+			alert(fmt.Sprintf("### New THORNode Version: `%s`\n> **Nodes:** %d (%d active)", v, all[v], active[v]))
+		}
+		if newLevel > level {
+			threshold := map[int]string{1: "1/3", 2: "2/3"}[newLevel]
+			alert(fmt.Sprintf("### Version Adoption: `%s`\n> **Active Nodes:** %d/%d, crossed %s", v, active[v], activeTotal, threshold))
+		}
+	}
+	// versions nobody runs any more can be reported again if they come back
+	for v := range vm.adoption {
+		if all[v] == 0 {
+			delete(vm.adoption, v)
+		}
+	}
+
+	if vm.initialized && version.Current != vm.current {
+		behind := 0
+		for v, count := range active {
+			if compareVersions(v, version.Current) < 0 {
+				behind += count
+			}
+		}
+		alert(fmt.Sprintf("### Network Version Upgraded\n> **Version:** `%s` → `%s`\n> **Next:** `%s`\n> **Active Nodes Behind:** %d/%d",
+			vm.current, version.Current, version.Next, behind, activeTotal))
+	}
+	vm.current = version.Current
+
+	if vm.initialized {
+		for _, msg := range proposalChanges(vm.proposals, proposals, height) {
+			alert(msg)
+		}
+	}
+	vm.proposals = make(map[string]common.UpgradeProposal, len(proposals))
+	for _, p := range proposals {
+		vm.proposals[p.Name] = p
+	}
+	vm.initialized = true
+
+	// watched nodes behind the current version are alerted once, and again once upgraded
+	byAddress := make(map[string]openapi.Node, len(nodes))
+	for _, node := range nodes {
+		byAddress[node.NodeAddress] = node
+	}
+	for _, watched := range vm.watched {
+		node, ok := byAddress[watched.Address]
+		if !ok || node.Version == "" {
+			continue
+		}
+		var msg string
+		prev, wasLagging := vm.lagging[watched.Address]
+		switch behind := compareVersions(node.Version, version.Current) < 0; {
+		case behind && !wasLagging:
+			vm.lagging[watched.Address] = node.Version
+			msg = fmt.Sprintf("> **Version Behind:** `%s` (network `%s`)", node.Version, version.Current)
+		case !behind && wasLagging:
+			delete(vm.lagging, watched.Address)
+			msg = fmt.Sprintf("> **Version Upgraded:** `%s` → `%s`", prev, node.Version)
+		default:
+			continue
+		}
+		msg = fmt.Sprintf("### Watched Node `%s`\n%s", watched.Address, msg)
+		alerts = append(alerts, notify.Alert{Webhooks: watchedNodeWebhooks(watched, cfg.Webhooks.Activity), Message: msg, Height: height})
+	}
+	return alerts, nil
+}
@@ -0,0 +1,156 @@
+package monitor
+
+import (
+	"fmt"
+	"public-alerts/internal/common"
+	"public-alerts/internal/config"
+	"strings"
+	"testing"
+
+	openapi "gitlab.com/thorchain/thornode/openapi/gen"
+)
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"1.131.0", "1.131.0", 0},
+		{"1.131.0", "1.132.0", -1},
+		{"1.140.0", "1.99.0", 1},
+		{"v2.0.0", "1.999.9", 1},
+		{"1.131", "1.131.1", -1},
+	}
+	for _, tt := range tests {
+		if got := compareVersions(tt.a, tt.b); got != tt.want {
+			t.Errorf("compareVersions(%s, %s) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestAdoptionLevel(t *testing.T) {
+	tests := []struct {
+		count, total, want int
+	}{
+		{0, 0, 0},
+		{32, 100, 0},
+		{34, 100, 1},
+		{66, 100, 1},
+		{67, 100, 2},
+	}
+	for _, tt := range tests {
+		if got := adoptionLevel(tt.count, tt.total); got != tt.want {
+			t.Errorf("adoptionLevel(%d, %d) = %d, want %d", tt.count, tt.total, got, tt.want)
+		}
+	}
+}
+
+func TestProposalChanges(t *testing.T) {
+	prev := map[string]common.UpgradeProposal{
+		"1.132.0": {Name: "1.132.0", Height: 2000, ApprovedPercent: "0.5", ValidatorsToQuorum: 2},
+		"1.133.0": {Name: "1.133.0", Height: 3000, ApprovedPercent: "0.1", ValidatorsToQuorum: 6},
+	}
+	proposals := []common.UpgradeProposal{
+		{Name: "1.132.0", Height: 2000, Approved: true, ApprovedPercent: "0.7"},
+		{Name: "1.133.0", Height: 3000, ApprovedPercent: "0.2", ValidatorsToQuorum: 5},
+		{Name: "1.134.0", Height: 4000, ApprovedPercent: "0", ValidatorsToQuorum: 7, Info: "bifrost fixes"},
+	}
+
+	msgs := proposalChanges(prev, proposals, 1000)
+	if len(msgs) != 3 {
+		t.Fatalf("expected approved, votes and proposed messages, got %v", msgs)
+	}
+	for i, want := range []string{
+		"### Upgrade Approved: `1.132.0`\n> **Scheduled Height:** 2000 (in 1000 blocks)\n> **Approval:** 50.0% → 70.0%",
+		"### Upgrade Votes: `1.133.0`\n> **Scheduled Height:** 3000 (in 2000 blocks)\n> **Approval:** 10.0% → 20.0%, 5 more validators to quorum",
+		"### Upgrade Proposed: `1.134.0`\n> **Scheduled Height:** 4000 (in 3000 blocks)\n> **Approval:** 0.0%, 7 more validators to quorum\n> **Info:** bifrost fixes",
+	} {
+		if msgs[i] != want {
+			t.Errorf("expected %q, got %q", want, msgs[i])
+		}
+	}
+
+	if msgs := proposalChanges(map[string]common.UpgradeProposal{"1.134.0": proposals[2]}, proposals[2:], 1000); len(msgs) != 0 {
+		t.Errorf("expected an unchanged proposal not to be reported, got %v", msgs)
+	}
+}
+
+func TestVersionMonitorCheck(t *testing.T) {
+	setVersions := func(client *common.FakeClient, upgraded int) {
+		client.Nodes = nil
+		for i := 0; i < 9; i++ {
+			version := "1.131.0"
+			if i < upgraded {
+				version = "1.132.0"
+			}
+			client.Nodes = append(client.Nodes, openapi.Node{NodeAddress: fmt.Sprintf("thor1node%d", i), Status: "Active", Version: version})
+		}
+	}
+
+	client := common.NewFakeClient()
+	client.Height = 100
+	client.Version = &openapi.VersionResponse{Current: "1.131.0", Next: "1.131.0"}
+	setVersions(client, 0)
+
+	watched := []config.WatchedNode{{Address: "thor1node8"}}
+	vm := NewVersionMonitor(client, watched)
+	alerts, err := vm.Check()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(alerts) != 0 {
+		t.Fatalf("expected the first check to only record state, got %v", alerts)
+	}
+
+	// a new version appears on three nodes, crossing a third of the active set
+	client.Height = 110
+	setVersions(client, 3)
+	client.Upgrades = []common.UpgradeProposal{{Name: "1.132.0", Height: 500, ApprovedPercent: "0.33", ValidatorsToQuorum: 3}}
+	alerts, err = vm.Check()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var titles []string
+	for _, alert := range alerts {
+		titles = append(titles, strings.SplitN(alert.Message, "\n", 2)[0])
+	}
+	expected := []string{"### New THORNode Version: `1.132.0`", "### Version Adoption: `1.132.0`", "### Upgrade Proposed: `1.132.0`"}
+	if strings.Join(titles, "|") != strings.Join(expected, "|") {
+		t.Fatalf("expected %v, got %v", expected, titles)
+	}
+
+	// the supermajority upgrades and the network version is bumped, leaving the watched node behind
+	client.Height = 500
+	setVersions(client, 6)
+	client.Version = &openapi.VersionResponse{Current: "1.132.0", Next: "1.132.0"}
+	client.Upgrades = []common.UpgradeProposal{{Name: "1.132.0", Height: 500, Approved: true, ApprovedPercent: "0.67"}}
+	alerts, err = vm.Check()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	titles = nil
+	for _, alert := range alerts {
+		titles = append(titles, strings.SplitN(alert.Message, "\n", 2)[0])
+	}
+	expected = []string{"### Version Adoption: `1.132.0`", "### Network Version Upgraded", "### Upgrade Approved: `1.132.0`", "### Watched Node `thor1node8`"}
+	if strings.Join(titles, "|") != strings.Join(expected, "|") {
+		t.Fatalf("expected %v, got %v", expected, titles)
+	}
+	if !strings.Contains(alerts[1].Message, "**Active Nodes Behind:** 3/9") {
+		t.Errorf("expected three active nodes behind, got %q", alerts[1].Message)
+	}
+	if !strings.Contains(alerts[3].Message, "**Version Behind:** `1.131.0` (network `1.132.0`)") {
+		t.Errorf("expected the watched node to be behind, got %q", alerts[3].Message)
+	}
+
+	// the watched node upgrades
+	client.Height = 510
+	setVersions(client, 9)
+	alerts, err = vm.Check()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(alerts) != 1 || !strings.Contains(alerts[0].Message, "**Version Upgraded:** `1.131.0` → `1.132.0`") {
+		t.Fatalf("expected the watched node upgrade, got %v", alerts)
+	}
+}
@@ -0,0 +1,89 @@
+package monitor
+
+import (
+	"fmt"
+	"public-alerts/internal/common"
+	"public-alerts/internal/config"
+	"public-alerts/internal/notify"
+
+	"github.com/rs/zerolog/log"
+)
+
+// WalletMonitor watches the RUNE balances of the configured node operator and bond
+// provider wallets. Operators need RUNE there to send the set-version and
+// set-ip-address transactions, so a balance below the minimum is alerted once until
+// it is topped up. A balance dropping by more than a few transaction fees between
+// checks is reported as an unexpected outgoing transfer.
+type WalletMonitor struct {
+	client  common.ThornodeDataFetcher
+	wallets []config.WatchedNode
+	last    map[string]int64 // balance at the last check, by address
+	low     map[string]bool  // low balances already alerted
+}
+
+func NewWalletMonitor(client common.ThornodeDataFetcher, wallets []config.WatchedNode) *WalletMonitor {
+	return &WalletMonitor{
+		client:  client,
+		wallets: wallets,
+		last:    make(map[string]int64),
+		low:     make(map[string]bool),
+	}
+}
+
+func (wm *WalletMonitor) Name() string {
+	return "WalletMonitor"
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// Helpers
+////////////////////////////////////////////////////////////////////////////////
+
+func formatRuneBalance(amount float64) string {
+	return fmt.Sprintf("%.2f RUNE", amount/1e8)
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// Check
+////////////////////////////////////////////////////////////////////////////////
+
+func (wm *WalletMonitor) Check() ([]notify.Alert, error) {
+	if len(wm.wallets) == 0 {
+		return nil, nil
+	}
+	log.Info().Msg("Checking operator wallets...")
+	cfg := config.Get()
+	minBalance, maxSpend := cfg.WalletMonitor.MinBalance*1e8, cfg.WalletMonitor.MaxSpend*1e8
+
+	client, height, err := common.PinLatestHeight(wm.client)
+	if err != nil {
+		return nil, err
+	}
+
+	var alerts []notify.Alert
+	for _, wallet := range wm.wallets {
+		balance, err := client.GetRuneBalance(wallet.Address)
+		if err != nil {
+			return nil, err
+		}
+		address := common.ShortenAddress(wallet.Address)
+
+		if prev, ok := wm.last[wallet.Address]; ok && float64(prev-balance) > maxSpend {
+			msg := fmt.Sprintf("### Outgoing Transfer: `%s`\n> **Balance:** %s → %s (-%s)\n> **Expected Spend:** up to %s between checks",
+				address, formatRuneBalance(float64(prev)), formatRuneBalance(float64(balance)),
+				formatRuneBalance(float64(prev-balance)), formatRuneBalance(maxSpend))
+			alerts = append(alerts, notify.Alert{Webhooks: watchedNodeWebhooks(wallet, cfg.Webhooks.Security), Message: msg, Height: height})
+		}
+		wm.last[wallet.Address] = balance
+
+		switch low := float64(balance) < minBalance; {
+		case low && !wm.low[wallet.Address]:
+			wm.low[wallet.Address] = true
+			msg := fmt.Sprintf("### Low Operator Wallet Balance: `%s`\n> **Balance:** %s (minimum %s)\n> **Needed For:** `make set-version` and `make set-ip-address` transactions",
+				address, formatRuneBalance(float64(balance)), formatRuneBalance(minBalance))
+			alerts = append(alerts, notify.Alert{Webhooks: watchedNodeWebhooks(wallet, cfg.Webhooks.Activity), Message: msg, Height: height})
+		case !low:
+			delete(wm.low, wallet.Address)
+		}
+	}
+	return alerts, nil
+}
@@ -0,0 +1,71 @@
+package monitor
+
+import (
+	"errors"
+	"public-alerts/internal/common"
+	"public-alerts/internal/config"
+	"testing"
+)
+
+func TestWalletMonitorCheck(t *testing.T) {
+	own := config.WatchedNode{Address: "thor1operatorwallet", Webhooks: config.Webhooks{Discord: "https://discord.example/mine"}}
+	provider := config.WatchedNode{Address: "thor1bondprovider"}
+
+	client := common.NewFakeClient()
+	client.Height = 1000
+	client.RuneBalances[own.Address] = 100e8
+	client.RuneBalances[provider.Address] = 3e8
+
+	wm := NewWalletMonitor(client, []config.WatchedNode{own, provider})
+	alerts, err := wm.Check()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := "### Low Operator Wallet Balance: `thor...ider`\n> **Balance:** 3.00 RUNE (minimum 5.00 RUNE)\n> **Needed For:** `make set-version` and `make set-ip-address` transactions"
+	if len(alerts) != 1 || alerts[0].Message != expected {
+		t.Fatalf("expected %q, got %v", expected, alerts)
+	}
+	if alerts[0].Webhooks != config.Get().Webhooks.Activity {
+		t.Errorf("expected the activity webhooks for a wallet without its own, got %v", alerts[0].Webhooks)
+	}
+
+	// a set-version fee is expected, the low balance is not repeated
+	client.Height = 1100
+	client.RuneBalances[own.Address] = 100e8 - 2000000
+	client.RuneBalances[provider.Address] = 3e8 - 2000000
+	if alerts, err = wm.Check(); err != nil || len(alerts) != 0 {
+		t.Fatalf("expected no alerts, got %v, %v", alerts, err)
+	}
+
+	client.Height = 1200
+	client.RuneBalances[own.Address] = 40e8
+	alerts, err = wm.Check()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected = "### Outgoing Transfer: `thor...llet`\n> **Balance:** 99.98 RUNE → 40.00 RUNE (-59.98 RUNE)\n> **Expected Spend:** up to 1.00 RUNE between checks"
+	if len(alerts) != 1 || alerts[0].Message != expected {
+		t.Fatalf("expected %q, got %v", expected, alerts)
+	}
+	if alerts[0].Webhooks != own.Webhooks {
+		t.Errorf("expected the wallet's own webhooks, got %v", alerts[0].Webhooks)
+	}
+	if alerts[0].Height != 1200 {
+		t.Errorf("expected height 1200, got %d", alerts[0].Height)
+	}
+
+	// topped up and drained again alerts again
+	client.RuneBalances[provider.Address] = 50e8
+	if alerts, err = wm.Check(); err != nil || len(alerts) != 0 {
+		t.Fatalf("expected no alerts after a top up, got %v, %v", alerts, err)
+	}
+	client.RuneBalances[provider.Address] = 4e8
+	if alerts, err = wm.Check(); err != nil || len(alerts) != 2 {
+		t.Fatalf("expected a transfer and a low balance alert, got %v, %v", alerts, err)
+	}
+
+	client.Err = errors.New("unavailable")
+	if _, err := wm.Check(); err == nil {
+		t.Error("expected error from client")
+	}
+}
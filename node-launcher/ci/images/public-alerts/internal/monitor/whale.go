@@ -0,0 +1,187 @@
+package monitor
+
+import (
+	"fmt"
+	"public-alerts/internal/common"
+	"public-alerts/internal/config"
+	"public-alerts/internal/notify"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+)
+
+// WhaleMonitor pages through the Midgard actions since the last poll and reports
+// swaps, liquidity additions and withdrawals, and savers deposits and withdrawals
+// above the configured USD values, grouped into one alert per poll.
+type WhaleMonitor struct {
+	thornode   common.ThornodeDataFetcher
+	midgard    common.MidgardDataFetcher
+	prices     common.PriceFetcher
+	lastHeight int64 // highest action height seen, 0 before the first check
+}
+
+func NewWhaleMonitor(thornode common.ThornodeDataFetcher, midgard common.MidgardDataFetcher, prices common.PriceFetcher) *WhaleMonitor {
+	return &WhaleMonitor{
+		thornode: thornode,
+		midgard:  midgard,
+		prices:   prices,
+	}
+}
+
+func (wm *WhaleMonitor) Name() string {
+	return "WhaleMonitor"
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// Helpers
+////////////////////////////////////////////////////////////////////////////////
+
+// largeAction is an action above its threshold.
+type largeAction struct {
+	height int64
+	line   string
+}
+
+// poolAsset returns the pool asset of a synth, trade or layer 1 asset.
+func poolAsset(asset string) string {
+	return strings.NewReplacer("/", ".", "~", ".").Replace(asset)
+}
+
+// coinsUSD returns the USD value of coins, skipping unpriced assets.
+func coinsUSD(txs []common.MidgardTransaction, prices map[string]float64) float64 {
+	total := 0.0
+	for _, tx := range txs {
+		for _, coin := range tx.Coins {
+			amount, err := strconv.ParseFloat(coin.Amount, 64)
+			if err != nil {
+				continue
+			}
+			total += amount / 1e8 * prices[poolAsset(coin.Asset)]
+		}
+	}
+	return total
+}
+
+func formatCoins(txs []common.MidgardTransaction) string {
+	var coins []string
+	for _, tx := range txs {
+		for _, coin := range tx.Coins {
+			amount, err := strconv.ParseFloat(coin.Amount, 64)
+			if err != nil {
+				continue
+			}
+			coins = append(coins, fmt.Sprintf("`%.2f %s`", amount/1e8, coin.Asset))
+		}
+	}
+	if len(coins) == 0 {
+		return "`pending`"
+	}
+	return strings.Join(coins, " + ")
+}
+
+// isSavers reports whether the action is on a savers vault, which Midgard names
+// by the synth asset, e.g. BTC/BTC.
+func isSavers(action common.MidgardAction) bool {
+	for _, pool := range action.Pools {
+		if strings.Contains(pool, "/") {
+			return true
+		}
+	}
+	return false
+}
+
+// classifyAction returns the kind of an action, its USD value, the threshold of
+// its kind and its description. ok is false for action types not monitored.
+func classifyAction(action common.MidgardAction, prices map[string]float64, cfg config.WhaleMonitorConfig) (kind string, value, threshold float64, description string, ok bool) {
+	switch action.Type {
+	case "swap":
+		return "Swap", coinsUSD(action.In, prices), cfg.SwapUSD, formatCoins(action.In) + " → " + formatCoins(action.Out), true
+	case "addLiquidity":
+		if isSavers(action) {
+			return "Savers Deposit", coinsUSD(action.In, prices), cfg.SaversUSD, formatCoins(action.In), true
+		}
+		return "Add Liquidity", coinsUSD(action.In, prices), cfg.LiquidityUSD, formatCoins(action.In), true
+	case "withdraw":
+		if isSavers(action) {
+			return "Savers Withdrawal", coinsUSD(action.Out, prices), cfg.SaversUSD, formatCoins(action.Out), true
+		}
+		return "Withdraw Liquidity", coinsUSD(action.Out, prices), cfg.LiquidityUSD, formatCoins(action.Out), true
+	}
+	return "", 0, 0, "", false
+}
+
+// actionTxID returns the THORChain transaction id of the action.
+func actionTxID(action common.MidgardAction) string {
+	for _, tx := range append(action.In, action.Out...) {
+		if tx.TxID != "" {
+			return tx.TxID
+		}
+	}
+	return ""
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// Check
+////////////////////////////////////////////////////////////////////////////////
+
+func (wm *WhaleMonitor) Check() ([]notify.Alert, error) {
+	log.Info().Msg("Checking large activity...")
+	cfg := config.Get()
+
+	// the first check starts from the current height, earlier actions are not reported
+	if wm.lastHeight == 0 {
+		height, err := wm.thornode.GetLatestHeight()
+		if err != nil {
+			return nil, err
+		}
+		wm.lastHeight = int64(height)
+		return nil, nil
+	}
+
+	actions, err := wm.midgard.GetActions(wm.lastHeight + 1)
+	if err != nil {
+		return nil, err
+	}
+	if len(actions) == 0 {
+		return nil, nil
+	}
+	prices, err := wm.prices.GetAssetPricesUSD()
+	if err != nil {
+		return nil, err
+	}
+
+	var large []largeAction
+	for _, action := range actions {
+		height, err := strconv.ParseInt(action.Height, 10, 64)
+		if err != nil {
+			log.Warn().Str("height", action.Height).Msg("skipping midgard action with invalid height")
+			continue
+		}
+		if height > wm.lastHeight {
+			wm.lastHeight = height
+		}
+		kind, value, threshold, description, ok := classifyAction(action, prices, cfg.WhaleMonitor)
+		if !ok || value < threshold {
+			continue
+		}
+		line := fmt.Sprintf("> **%s:** %s %s", kind, common.FormatUSD(value), description)
+		if txID := actionTxID(action); txID != "" {
+			line += fmt.Sprintf("\n> **Tx:** %s", explorerTxLink(cfg, txID))
+		}
+		large = append(large, largeAction{height: height, line: line})
+	}
+	if len(large) == 0 {
+		return nil, nil
+	}
+
+	// Midgard returns the newest actions first, report them in the order they happened
+	sort.SliceStable(large, func(i, j int) bool { return large[i].height < large[j].height })
+	lines := make([]string, 0, len(large))
+	for _, a := range large {
+		lines = append(lines, a.line)
+	}
+	msg := "### Large Activity\n" + strings.Join(lines, "\n")
+	return []notify.Alert{{Webhooks: cfg.Webhooks.Activity, Message: msg, Height: int(wm.lastHeight)}}, nil
+}
@@ -0,0 +1,98 @@
+package monitor
+
+import (
+	"public-alerts/internal/common"
+	"public-alerts/internal/config"
+	"testing"
+)
+
+func midgardTx(txID string, coins ...common.MidgardCoin) common.MidgardTransaction {
+	return common.MidgardTransaction{TxID: txID, Coins: coins}
+}
+
+func TestClassifyAction(t *testing.T) {
+	cfg := config.NewWhaleMonitorConfig()
+	prices := map[string]float64{"BTC.BTC": 60000, common.RuneAsset: 5}
+	btc := common.MidgardCoin{Asset: "BTC.BTC", Amount: "500000000"}
+	synth := common.MidgardCoin{Asset: "BTC/BTC", Amount: "200000000"}
+	runeCoin := common.MidgardCoin{Asset: "THOR.RUNE", Amount: "6000000000000"}
+
+	tests := []struct {
+		name   string
+		action common.MidgardAction
+		kind   string
+		value  float64
+	}{
+		{"swap", common.MidgardAction{Type: "swap", In: []common.MidgardTransaction{midgardTx("A", btc)}}, "Swap", 300000},
+		{"add liquidity", common.MidgardAction{Type: "addLiquidity", Pools: []string{"BTC.BTC"}, In: []common.MidgardTransaction{midgardTx("A", btc), midgardTx("B", runeCoin)}}, "Add Liquidity", 600000},
+		{"savers deposit", common.MidgardAction{Type: "addLiquidity", Pools: []string{"BTC/BTC"}, In: []common.MidgardTransaction{midgardTx("A", btc)}}, "Savers Deposit", 300000},
+		{"savers withdrawal", common.MidgardAction{Type: "withdraw", Pools: []string{"BTC/BTC"}, Out: []common.MidgardTransaction{midgardTx("A", synth)}}, "Savers Withdrawal", 120000},
+		{"withdraw", common.MidgardAction{Type: "withdraw", Pools: []string{"BTC.BTC"}, Out: []common.MidgardTransaction{midgardTx("A", runeCoin)}}, "Withdraw Liquidity", 300000},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kind, value, _, _, ok := classifyAction(tt.action, prices, cfg)
+			if !ok || kind != tt.kind || value != tt.value {
+				t.Errorf("expected %s worth %v, got %s worth %v (%v)", tt.kind, tt.value, kind, value, ok)
+			}
+		})
+	}
+
+	if _, _, _, _, ok := classifyAction(common.MidgardAction{Type: "refund"}, prices, cfg); ok {
+		t.Error("expected refunds not to be monitored")
+	}
+}
+
+func TestWhaleMonitorCheck(t *testing.T) {
+	thornode := common.NewFakeClient()
+	thornode.Height = 100
+	midgard := common.NewFakeClient()
+	midgard.Prices = map[string]float64{"BTC.BTC": 60000, common.RuneAsset: 5}
+	btc := func(amount string) common.MidgardCoin { return common.MidgardCoin{Asset: "BTC.BTC", Amount: amount} }
+	runeCoin := func(amount string) common.MidgardCoin { return common.MidgardCoin{Asset: "THOR.RUNE", Amount: amount} }
+
+	// actions before the monitor started are not reported
+	midgard.Actions = []common.MidgardAction{
+		{Height: "99", Type: "swap", In: []common.MidgardTransaction{midgardTx("OLD", btc("1000000000"))}},
+	}
+	wm := NewWhaleMonitor(thornode, midgard, midgard)
+	alerts, err := wm.Check()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(alerts) != 0 {
+		t.Fatalf("expected the first check to only record the height, got %v", alerts)
+	}
+
+	// newest first, as served by Midgard
+	midgard.Actions = append([]common.MidgardAction{
+		{Height: "110", Type: "swap", In: []common.MidgardTransaction{midgardTx("SMALL", btc("10000000"))}},
+		{Height: "105", Type: "swap", Status: "success",
+			In:  []common.MidgardTransaction{midgardTx("BIG", btc("500000000"))},
+			Out: []common.MidgardTransaction{midgardTx("OUT", runeCoin("6000000000000"))}},
+		{Height: "103", Type: "withdraw", Pools: []string{"BTC/BTC"}, Out: []common.MidgardTransaction{midgardTx("SAVER", btc("200000000"))}},
+	}, midgard.Actions...)
+
+	alerts, err = wm.Check()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(alerts) != 1 {
+		t.Fatalf("expected one grouped alert, got %v", alerts)
+	}
+	expected := "### Large Activity\n" +
+		"> **Savers Withdrawal:** $120000 `2.00 BTC.BTC`\n> **Tx:** /tx/SAVER\n" +
+		"> **Swap:** $300000 `5.00 BTC.BTC` → `60000.00 THOR.RUNE`\n> **Tx:** /tx/BIG"
+	if alerts[0].Message != expected || alerts[0].Height != 110 {
+		t.Errorf("expected %q at 110, got %q at %d", expected, alerts[0].Message, alerts[0].Height)
+	}
+
+	// the same actions are not reported again
+	alerts, err = wm.Check()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(alerts) != 0 {
+		t.Fatalf("expected no repeated alerts, got %v", alerts)
+	}
+}
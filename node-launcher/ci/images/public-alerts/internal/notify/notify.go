@@ -12,6 +12,15 @@ import (
 type Alert struct {
 	Webhooks config.Webhooks
 	Message  string
+	Height   int // THORChain height the alert was evaluated at, 0 if not pinned
+}
+
+// Text returns the message to post, noting the evaluated height when set.
+func (a Alert) Text() string {
+	if a.Height > 0 {
+		return fmt.Sprintf("%s\n_height %d_", a.Message, a.Height)
+	}
+	return a.Message
 }
 
 func Notify(alert Alert) []error {
@@ -29,11 +38,11 @@ func Notify(alert Alert) []error {
 	// Start goroutines for each webhook
 	if alert.Webhooks.Slack != "" {
 		wg.Add(1)
-		go notifyConcurrently(alert.Webhooks.Slack, map[string]string{"text": alert.Message})
+		go notifyConcurrently(alert.Webhooks.Slack, map[string]string{"text": alert.Text()})
 	}
 	if alert.Webhooks.Discord != "" {
 		wg.Add(1)
-		go notifyConcurrently(alert.Webhooks.Discord, map[string]string{"content": alert.Message})
+		go notifyConcurrently(alert.Webhooks.Discord, map[string]string{"content": alert.Text()})
 	}
 
 	// Wait for all goroutines to finish
@@ -0,0 +1,15 @@
+package notify
+
+import "testing"
+
+func TestAlertText(t *testing.T) {
+	alert := Alert{Message: "```stuck```"}
+	if alert.Text() != "```stuck```" {
+		t.Errorf("expected unpinned alert to be unchanged, got %q", alert.Text())
+	}
+
+	alert.Height = 100
+	if alert.Text() != "```stuck```\n_height 100_" {
+		t.Errorf("expected height to be appended, got %q", alert.Text())
+	}
+}